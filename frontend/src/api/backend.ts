@@ -14,13 +14,37 @@ export interface SystemInfo {
   public_ip: string
   ip_location: string
   ip_operator: string
+  privacy_mode: boolean // 隐私模式下不查询公网 IP
 }
 
+export type RiskLevel = 'low' | 'medium' | 'high'
+
 export interface ScanResult {
   category: string
+  category_name: string
   items: JunkItem[]
   size: number
   count: number
+  risk: RiskLevel
+  description: string
+}
+
+export interface RecommendItem {
+  category: string
+  category_name: string
+  risk: RiskLevel
+  size: number
+  recommended: boolean
+  reason: string
+}
+
+export interface CleanRecommendation {
+  items: RecommendItem[] | null
+  recommended: string[] | null
+  recommended_size: number
+  disk_free: number
+  disk_total: number
+  low_disk: boolean
 }
 
 export interface JunkItem {
@@ -33,6 +57,32 @@ export interface CleanResult {
   freed_size: number
   cleaned_count: number
   failed_count: number
+  categories: CategoryFreed[] | null
+  failures: CleanFailure[] | null
+}
+
+export interface FileLocker {
+  pid: number
+  name: string
+  app_name: string
+  service: string
+  username: string
+}
+
+export interface CleanFailure {
+  path: string
+  size: number
+  category: string
+  error: string
+  in_use: boolean
+  holders: FileLocker[] | null
+}
+
+export interface CategoryFreed {
+  category: string
+  category_name?: string
+  freed_size: number
+  cleaned_count: number
 }
 
 export interface MemoryOptResult {
@@ -78,14 +128,35 @@ export interface MonthlyStat {
   count: number
 }
 
+export interface CategoryPoint {
+  time: string
+  freed_size: number
+  count: number
+}
+
+export interface CategoryTrend {
+  category: string
+  category_name: string
+  total_freed: number
+  total_count: number
+  clean_times: number
+  last_clean_time: string
+  avg_freed: number
+  regrowth_per_day: number
+  regrowth_weekly: number
+  suggest_days: number
+  points: CategoryPoint[]
+}
+
 export interface CleanHistoryStats {
-  records: { date: string; time: string; freed_size: number; cleaned_count: number }[]
+  records: { timestamp: string; freed_size: number; cleaned_count: number; categories?: CategoryFreed[] }[]
   daily_stats: DailyStat[]
   monthly_stats: MonthlyStat[]
   last_clean_time: string
   last_clean_ago: string
   total_freed: number
   total_count: number
+  category_trends: CategoryTrend[] | null
 }
 
 export interface RealtimeStats {
@@ -132,12 +203,71 @@ export interface NetYearlyStat {
   recv: number
 }
 
+export interface NetTrafficGap {
+  start: string
+  end: string
+  iface?: string
+  reason: 'reboot' | 'counter_reset' | 'implausible'
+}
+
 export interface NetTrafficStats {
   daily_stats: NetDailyStat[]
   monthly_stats: NetMonthlyStat[]
   yearly_stats: NetYearlyStat[]
   total_sent: number
   total_recv: number
+  gaps: NetTrafficGap[] | null
+  interfaces: NetInterfaceStats[] | null
+}
+
+export interface NetInterfaceStats {
+  name: string
+  virtual: boolean
+  included: boolean
+  daily_stats: NetDailyStat[] | null
+  monthly_stats: NetMonthlyStat[] | null
+  yearly_stats: NetYearlyStat[] | null
+  total_sent: number
+  total_recv: number
+}
+
+export interface NetInterfaceInfo {
+  name: string
+  virtual: boolean
+  included: boolean
+  bytes_sent: number
+  bytes_recv: number
+}
+
+export interface NetBudget {
+  iface: string
+  budget_bytes: number
+  cycle_start_day: number
+  sent_weight: number
+  recv_weight: number
+}
+
+export interface NetBudgetStatus {
+  budget: NetBudget
+  cycle_start: string
+  cycle_end: string
+  days_left: number
+  used_bytes: number
+  used_percent: number
+  daily_rate: number
+  projected_bytes: number
+  projected_percent: number
+  level: number
+}
+
+export interface NetBudgetAlert {
+  iface: string
+  threshold: number
+  used_bytes: number
+  budget_bytes: number
+  used_percent: number
+  cycle_start: string
+  cycle_end: string
 }
 
 export interface DiskInfo {
@@ -162,8 +292,7 @@ export interface DiskScanResult {
 }
 
 export interface MemOptRecord {
-  date: string
-  time: string
+  timestamp: string
   freed_mb: number
   before_percent: number
   after_percent: number
@@ -201,16 +330,387 @@ export interface UpdateInfo {
 
 export interface PortInfo {
   listen_addr: string
+  local_ip: string
   port: number
-  proto: string
+  remote_addr: string
+  remote_ip: string
+  remote_port: number
+  proto: 'tcp' | 'udp'
+  family: 'ipv4' | 'ipv6'
   pid: number
   process_name: string
   status: string
+  listening: boolean
+}
+
+export interface PortFilter {
+  proto?: 'tcp' | 'udp' | ''
+  family?: 'ipv4' | 'ipv6' | ''
+  states?: string[]
+  pid?: number
+  min_port?: number
+  max_port?: number
+  listening_only?: boolean
 }
 
 export interface PortScanResult {
   ports: PortInfo[]
   count: number
+  tcp_count: number
+  udp_count: number
+  listen_count: number
+  state_counts: Record<string, number>
+  scan_time: string
+}
+
+export interface KillPortOptions {
+  listening_only?: boolean
+  proto?: 'tcp' | 'udp' | ''
+  graceful_timeout_ms?: number
+  force?: boolean
+  tree?: boolean
+  override?: boolean // 用户已确认，允许结束受保护的关键进程
+  pids?: number[] // 只结束这些进程，为空时结束该端口的全部进程
+}
+
+export type KillStatus = 'killed' | 'already_exited' | 'access_denied' | 'protected' | 'failed'
+
+export interface ProcessKillResult {
+  pid: number
+  name: string
+  status: KillStatus
+  method?: 'graceful' | 'force'
+  children?: number[]
+  error?: string
+  overridable?: boolean // 受保护，确认后可用 override 重试
+}
+
+export interface ProcessProtection {
+  critical?: string[]
+  allow: string[]
+  block: string[]
+}
+
+export interface ProtectedProcessInfo {
+  pid: number
+  name: string
+  reason: 'self' | 'system' | 'critical' | 'blocklist' | 'unknown'
+  overridable: boolean
+}
+
+// 后端带代码的错误（见 app.FormatError），其余错误为字符串
+export interface AppError {
+  code: string
+  message: string
+  data?: any
+}
+
+export const isAppError = (e: unknown): e is AppError =>
+  typeof e === 'object' && e !== null && 'code' in e && 'message' in e
+
+// geoLabel 归属地简短描述，如 "美国 California · AS15169 GOOGLE"
+export const geoLabel = (g?: GeoIPInfo): string => {
+  if (!g || !g.source) return ''
+  const place = [g.country, g.region, g.city].filter((v, i, a) => v && a.indexOf(v) === i).join(' ')
+  const as = g.asn ? `AS${g.asn} ${g.as_org ?? ''}`.trim() : ''
+  return [place, as].filter(Boolean).join(' · ')
+}
+
+export const errorMessage = (e: unknown): string => (isAppError(e) ? e.message : String(e))
+
+export const isProtectedProcessError = (e: unknown): e is AppError & { data: ProtectedProcessInfo } =>
+  isAppError(e) && e.code === 'protected_process'
+
+export interface KillPortResult {
+  port: number
+  killed: number
+  results: ProcessKillResult[]
+}
+
+export interface WatchedPort {
+  port: number
+  label: string
+}
+
+export interface PortHolder {
+  pid: number
+  name: string
+  cmdline: string
+  proto: string
+  listen_addr: string
+}
+
+export interface PortHolderRecord extends PortHolder {
+  port: number
+  start: string
+  end: string // 仍在占用时为空
+}
+
+export interface PortWatchStatus extends WatchedPort {
+  occupied: boolean
+  holders: PortHolder[]
+  history: PortHolderRecord[] // 最近的在前
+}
+
+export interface PortWatchEvent extends WatchedPort {
+  event: 'occupied' | 'freed' | 'changed'
+  holders: PortHolder[]
+  time: string
+}
+
+export interface ServiceFingerprint {
+  port: number
+  address: string // 实际连接的回环地址
+  service: 'http' | 'https' | 'tls' | 'redis' | 'postgresql' | 'mysql' | 'ssh' | 'unknown'
+  version?: string
+  banner?: string
+  http_status?: number
+  server?: string
+  title?: string
+  tls_subject?: string
+  tls_issuer?: string
+  tls_not_after?: string
+  tls_expired?: boolean
+  error?: string
+}
+
+export interface PortForwardRule {
+  id: string
+  listen_addr: string // 如 0.0.0.0:8080
+  target_addr: string // 如 127.0.0.1:3000
+  persist: boolean // 下次启动自动恢复
+  created: string
+}
+
+export interface PortForwardStatus extends PortForwardRule {
+  running: boolean
+  error?: string // 恢复时启动失败的原因
+  active: number
+  total: number
+  bytes_in: number // 客户端 → 目标
+  bytes_out: number // 目标 → 客户端
+}
+
+export interface GeoIPInfo {
+  ip: string
+  country_code?: string
+  country?: string
+  region?: string
+  city?: string
+  asn?: number
+  as_org?: string
+  private?: boolean // 内网、回环等非公网地址
+  source?: 'offline' | 'online' // 未查到时为空
+}
+
+export interface PublicIPInfo {
+  ip: string
+  provider: string
+  location: string
+  operator: string
+  checked_at: string
+  stale: boolean // 本次全部失败，返回上次的结果
+  disabled: boolean // 隐私模式，未查询
+  error?: string
+}
+
+export interface GeoIPDatabase {
+  file: string
+  type: string
+  build_time: string
+}
+
+export interface GeoIPStatus {
+  dir: string // 放置 .mmdb 文件的目录
+  databases: GeoIPDatabase[]
+  online_fallback: boolean
+}
+
+export interface ConnRecord {
+  process: string
+  proto: string
+  remote_ip: string
+  remote_port: number // 入站连接为 0
+  local_port?: number // 入站连接的本机监听端口
+  inbound: boolean
+  first_seen: string
+  last_seen: string
+  count: number // 被采样到的次数
+}
+
+export interface ConnHistoryQuery {
+  group_by?: 'process' | 'remote'
+  process?: string
+  remote_ip?: string // IP 前缀
+  remote_port?: number
+  days?: number
+  outbound_only?: boolean
+}
+
+export interface ConnHistoryGroup {
+  key: string // 进程名或远端 IP
+  members: number // 按进程分组时为远端主机数，按远端分组时为进程数
+  count: number
+  first_seen: string
+  last_seen: string
+  records: ConnRecord[]
+  geo?: GeoIPInfo // 按远端主机分组时的归属地
+}
+
+export interface ConnHistoryResult {
+  group_by: 'process' | 'remote'
+  total: number
+  groups: ConnHistoryGroup[]
+}
+
+export interface PortRange {
+  start: number
+  end: number
+  proto: string
+}
+
+export interface FreePortRequest {
+  start: number
+  end: number
+  proto?: 'tcp' | 'udp' | 'both'
+  interfaces?: string[] // 网卡名或 IP 地址，为空时为 0.0.0.0
+  count?: number
+  hold?: boolean // 保持监听占住端口，直到 releasePort
+}
+
+export interface FreePortResult {
+  ports: number[]
+  addresses: string[]
+  excluded: PortRange[]
+  reserved: number
+  in_use: number
+  held: boolean
+}
+
+export interface HeldPort {
+  port: number
+  proto: string
+  addresses: string[]
+  since: string
+}
+
+export interface DownloadFileInfo {
+  path: string
+  name: string
+  size: number
+  ext: string
+  file_type: 'installer' | 'archive' | 'disk_image' | 'document' | 'media' | 'other'
+  mod_time: string
+  age_days: number
+  age_bucket: 'week' | 'month' | 'quarter' | 'older'
+  matched_app: string
+  suggest: boolean
+  reason: string
+}
+
+export interface DownloadGroup {
+  file_type: string
+  age_bucket: string
+  count: number
+  size: number
+}
+
+export interface DownloadsReport {
+  dir: string
+  files: DownloadFileInfo[] | null
+  groups: DownloadGroup[] | null
+  suggestions: DownloadFileInfo[] | null
+  total_size: number
+  total_count: number
+  suggest_size: number
+  installed_apps: number
+}
+
+export interface DirSize {
+  path: string
+  category?: string
+  size: number
+  count: number
+}
+
+export interface CategorySnapshot {
+  category: string
+  size: number
+  count: number
+  top_dirs: DirSize[]
+}
+
+export interface ScanSnapshot {
+  id: string
+  kind: 'junk' | 'large'
+  time: string
+  root?: string
+  min_size_mb?: number
+  total_size: number
+  total_count: number
+  categories?: CategorySnapshot[]
+  files?: LargeFileInfo[]
+}
+
+export interface CategoryDelta {
+  category: string
+  category_name: string
+  old_size: number
+  new_size: number
+  size_delta: number
+  old_count: number
+  new_count: number
+  count_delta: number
+}
+
+export interface ScanDiff {
+  old_id: string
+  old_time: string
+  new_id: string
+  new_time: string
+  kind: 'junk' | 'large'
+  size_delta: number
+  category_deltas: CategoryDelta[] | null
+  added_dirs: DirSize[] | null
+  removed_dirs: DirSize[] | null
+  added_files: LargeFileInfo[] | null
+  removed_files: LargeFileInfo[] | null
+}
+
+export interface Settings {
+  net_sample_interval: number
+  public_ip_cache_minutes: number
+  mem_history_days: number
+  listening_port_min: number
+  large_file_limit: number
+  cpu_sample_ms: number
+  port_watch_interval: number
+  conn_sample_interval: number
+  conn_history_days: number
+  net_minute_days: number // 分钟级流量记录保留天数，之后汇总为小时
+  net_hourly_days: number // 小时级流量记录保留天数，之后汇总为天
+  geoip_online: boolean // 允许在线查询用户点选的 IP 和本机公网 IP
+  public_ip_providers: string // 依次尝试的公网 IP 服务，逗号分隔：ipify、ifconfig.co、custom
+  public_ip_custom_url: string // 自建公网 IP 服务地址（HTTPS）
+  privacy_mode: boolean // 不发出任何外网请求（公网 IP、在线归属地、检查更新）
+  locale: 'zh-CN' | 'en-US'
+}
+
+export interface DataDirInfo {
+  path: string
+  source: 'flag' | 'env' | 'portable' | 'home' | 'fallback'
+  migration_error?: string // 数据格式升级失败的原因，失败时暂停写入清理/内存优化历史
+}
+
+export interface ImportEntryStats {
+  name: string
+  added: number
+  skipped: number
+}
+
+export interface ImportResult {
+  mode: 'merge' | 'replace'
+  entries: ImportEntryStats[] | null
 }
 
 declare global {
@@ -220,10 +720,12 @@ declare global {
         App: {
           GetSystemInfo(): Promise<SystemInfo>
           ScanJunk(): Promise<ScanResult[]>
-          CleanJunk(categories: string[]): Promise<CleanResult>
+          CleanJunk(categoryIDs: string[]): Promise<CleanResult>
           OptimizeMemory(): Promise<MemoryOptResult>
           GetProcessList(): Promise<ProcessInfo[]>
-          KillProcess(pid: number): Promise<void>
+          KillProcess(pid: number, override: boolean): Promise<void>
+          GetProcessProtection(): Promise<ProcessProtection>
+          SetProcessProtection(p: ProcessProtection): Promise<void>
           GetGPUInfo(): Promise<GPUResult>
           GetCleanHistory(): Promise<CleanHistoryStats>
           GetRealtimeStats(): Promise<RealtimeStats>
@@ -235,11 +737,52 @@ declare global {
           GetAppVersion(): Promise<string>
           CheckUpdate(): Promise<UpdateInfo>
           GetPortList(port: number): Promise<PortInfo[]>
-          KillProcessesByPort(port: number): Promise<number>
+          KillProcessesByPort(port: number, opts: KillPortOptions): Promise<KillPortResult>
           GetListeningPorts(minPort: number): Promise<PortInfo[]>
+          GetPortWatchlist(): Promise<PortWatchStatus[]>
+          AddWatchedPort(port: number, label: string): Promise<void>
+          RemoveWatchedPort(port: number): Promise<void>
+          FingerprintPorts(ports: number[]): Promise<ServiceFingerprint[]>
+          CreatePortForward(listenAddr: string, targetAddr: string, persist: boolean): Promise<PortForwardStatus>
+          ListPortForwards(): Promise<PortForwardStatus[]>
+          StopPortForward(id: string): Promise<void>
+          QueryConnHistory(q: ConnHistoryQuery): Promise<ConnHistoryResult>
+          LookupGeoIP(ips: string[]): Promise<GeoIPInfo[]>
+          LookupGeoIPOnline(ip: string): Promise<GeoIPInfo>
+          GetPublicIP(force: boolean): Promise<PublicIPInfo>
+          GetPublicIPProviders(): Promise<string[]>
+          SetPrivacyMode(on: boolean): Promise<Settings>
+          GetGeoIPStatus(): Promise<GeoIPStatus>
+          ClearConnHistory(): Promise<void>
+          FindFreePorts(req: FreePortRequest): Promise<FreePortResult>
+          GetHeldPorts(): Promise<HeldPort[]>
+          ReleasePort(port: number): Promise<void>
+          GetConnections(filter: PortFilter): Promise<PortScanResult>
+          AnalyzeDownloads(): Promise<DownloadsReport>
+          ListScanSnapshots(kind: string): Promise<ScanSnapshot[]>
+          CompareScans(oldID: string, newID: string): Promise<ScanDiff>
+          GetCleanRecommendation(): Promise<CleanRecommendation>
+          RetryClean(items: JunkItem[]): Promise<CleanResult>
+          WhoIsUsingFile(path: string): Promise<FileLocker[]>
+          GetNetInterfaces(): Promise<NetInterfaceInfo[]>
+          SetNetInterfaceIncluded(name: string, included: boolean): Promise<void>
+          GetNetBudgets(): Promise<NetBudgetStatus[]>
+          SetNetBudget(budget: NetBudget): Promise<void>
+          DeleteNetBudget(iface: string): Promise<void>
+          GetSettings(): Promise<Settings>
+          UpdateSettings(settings: Settings): Promise<Settings>
+          GetLocales(): Promise<string[]>
+          SetLocale(locale: string): Promise<Settings>
+          GetDataDirInfo(): Promise<DataDirInfo>
+          ExportData(): Promise<string>
+          ImportData(mode: string): Promise<ImportResult | null>
+          ExportCSV(kind: string): Promise<string>
         }
       }
     }
+    runtime: {
+      EventsOn(event: string, callback: (...data: any[]) => void): () => void
+    }
   }
 }
 
@@ -250,8 +793,8 @@ export const api = {
   scanJunk: (): Promise<ScanResult[]> =>
     window.go.app.App.ScanJunk(),
 
-  cleanJunk: (categories: string[]): Promise<CleanResult> =>
-    window.go.app.App.CleanJunk(categories),
+  cleanJunk: (categoryIDs: string[]): Promise<CleanResult> =>
+    window.go.app.App.CleanJunk(categoryIDs),
 
   optimizeMemory: (): Promise<MemoryOptResult> =>
     window.go.app.App.OptimizeMemory(),
@@ -259,8 +802,14 @@ export const api = {
   getProcessList: (): Promise<ProcessInfo[]> =>
     window.go.app.App.GetProcessList(),
 
-  killProcess: (pid: number): Promise<void> =>
-    window.go.app.App.KillProcess(pid),
+  killProcess: (pid: number, override = false): Promise<void> =>
+    window.go.app.App.KillProcess(pid, override),
+
+  getProcessProtection: (): Promise<ProcessProtection> =>
+    window.go.app.App.GetProcessProtection(),
+
+  setProcessProtection: (p: ProcessProtection): Promise<void> =>
+    window.go.app.App.SetProcessProtection(p),
 
   getGPUInfo: (): Promise<GPUResult> =>
     window.go.app.App.GetGPUInfo(),
@@ -295,9 +844,133 @@ export const api = {
   getPortList: (port: number): Promise<PortInfo[]> =>
     window.go.app.App.GetPortList(port),
 
-  killProcessesByPort: (port: number): Promise<number> =>
-    window.go.app.App.KillProcessesByPort(port),
+  killProcessesByPort: (port: number, opts: KillPortOptions = {}): Promise<KillPortResult> =>
+    window.go.app.App.KillProcessesByPort(port, opts),
 
   getListeningPorts: (minPort: number = 1000): Promise<PortInfo[]> =>
     window.go.app.App.GetListeningPorts(minPort),
+
+  getPortWatchlist: (): Promise<PortWatchStatus[]> =>
+    window.go.app.App.GetPortWatchlist(),
+
+  addWatchedPort: (port: number, label: string = ''): Promise<void> =>
+    window.go.app.App.AddWatchedPort(port, label),
+
+  removeWatchedPort: (port: number): Promise<void> =>
+    window.go.app.App.RemoveWatchedPort(port),
+
+  fingerprintPorts: (ports: number[]): Promise<ServiceFingerprint[]> =>
+    window.go.app.App.FingerprintPorts(ports),
+
+  createPortForward: (listenAddr: string, targetAddr: string, persist: boolean = false): Promise<PortForwardStatus> =>
+    window.go.app.App.CreatePortForward(listenAddr, targetAddr, persist),
+
+  listPortForwards: (): Promise<PortForwardStatus[]> =>
+    window.go.app.App.ListPortForwards(),
+
+  stopPortForward: (id: string): Promise<void> =>
+    window.go.app.App.StopPortForward(id),
+
+  queryConnHistory: (q: ConnHistoryQuery = {}): Promise<ConnHistoryResult> =>
+    window.go.app.App.QueryConnHistory(q),
+
+  clearConnHistory: (): Promise<void> =>
+    window.go.app.App.ClearConnHistory(),
+
+  lookupGeoIP: (ips: string[]): Promise<GeoIPInfo[]> =>
+    window.go.app.App.LookupGeoIP(ips),
+
+  // 单个 IP，本地查不到且设置允许时在线查询（明文 HTTP），只用于用户主动点选
+  lookupGeoIPOnline: (ip: string): Promise<GeoIPInfo> =>
+    window.go.app.App.LookupGeoIPOnline(ip),
+
+  getPublicIP: (force: boolean = false): Promise<PublicIPInfo> =>
+    window.go.app.App.GetPublicIP(force),
+
+  getPublicIPProviders: (): Promise<string[]> =>
+    window.go.app.App.GetPublicIPProviders(),
+
+  setPrivacyMode: (on: boolean): Promise<Settings> =>
+    window.go.app.App.SetPrivacyMode(on),
+
+  getGeoIPStatus: (): Promise<GeoIPStatus> =>
+    window.go.app.App.GetGeoIPStatus(),
+
+  findFreePorts: (req: FreePortRequest): Promise<FreePortResult> =>
+    window.go.app.App.FindFreePorts(req),
+
+  getHeldPorts: (): Promise<HeldPort[]> =>
+    window.go.app.App.GetHeldPorts(),
+
+  releasePort: (port: number): Promise<void> =>
+    window.go.app.App.ReleasePort(port),
+
+  getConnections: (filter: PortFilter = {}): Promise<PortScanResult> =>
+    window.go.app.App.GetConnections(filter),
+
+  analyzeDownloads: (): Promise<DownloadsReport> =>
+    window.go.app.App.AnalyzeDownloads(),
+
+  listScanSnapshots: (kind: '' | 'junk' | 'large' = ''): Promise<ScanSnapshot[]> =>
+    window.go.app.App.ListScanSnapshots(kind),
+
+  compareScans: (oldID = '', newID = ''): Promise<ScanDiff> =>
+    window.go.app.App.CompareScans(oldID, newID),
+
+  getCleanRecommendation: (): Promise<CleanRecommendation> =>
+    window.go.app.App.GetCleanRecommendation(),
+
+  retryClean: (items: JunkItem[]): Promise<CleanResult> =>
+    window.go.app.App.RetryClean(items),
+
+  whoIsUsingFile: (path: string): Promise<FileLocker[]> =>
+    window.go.app.App.WhoIsUsingFile(path),
+
+  getNetInterfaces: (): Promise<NetInterfaceInfo[]> =>
+    window.go.app.App.GetNetInterfaces(),
+
+  setNetInterfaceIncluded: (name: string, included: boolean): Promise<void> =>
+    window.go.app.App.SetNetInterfaceIncluded(name, included),
+
+  getNetBudgets: (): Promise<NetBudgetStatus[]> =>
+    window.go.app.App.GetNetBudgets(),
+
+  setNetBudget: (budget: NetBudget): Promise<void> =>
+    window.go.app.App.SetNetBudget(budget),
+
+  deleteNetBudget: (iface: string): Promise<void> =>
+    window.go.app.App.DeleteNetBudget(iface),
+
+  getSettings: (): Promise<Settings> =>
+    window.go.app.App.GetSettings(),
+
+  updateSettings: (settings: Settings): Promise<Settings> =>
+    window.go.app.App.UpdateSettings(settings),
+
+  getLocales: (): Promise<string[]> =>
+    window.go.app.App.GetLocales(),
+
+  setLocale: (locale: string): Promise<Settings> =>
+    window.go.app.App.SetLocale(locale),
+
+  getDataDirInfo: (): Promise<DataDirInfo> =>
+    window.go.app.App.GetDataDirInfo(),
+
+  exportData: (): Promise<string> =>
+    window.go.app.App.ExportData(),
+
+  importData: (mode: 'merge' | 'replace'): Promise<ImportResult | null> =>
+    window.go.app.App.ImportData(mode),
+
+  exportCSV: (kind: 'clean' | 'memory' | 'network'): Promise<string> =>
+    window.go.app.App.ExportCSV(kind),
+
+  onNetBudgetAlert: (callback: (alert: NetBudgetAlert) => void): (() => void) =>
+    window.runtime.EventsOn('net:budget-alert', callback),
+
+  onPortWatchEvent: (callback: (event: PortWatchEvent) => void): (() => void) =>
+    window.runtime.EventsOn('port:watch', callback),
+
+  onSettingsChanged: (callback: (settings: Settings) => void): (() => void) =>
+    window.runtime.EventsOn('settings:changed', callback),
 }
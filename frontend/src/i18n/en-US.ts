@@ -0,0 +1,298 @@
+export default {
+  // Sidebar
+  'app.subtitle': 'System cleanup tool',
+  'nav.dashboard': 'Overview',
+  'nav.cleaner': 'Junk Cleaner',
+  'nav.memory': 'Memory',
+  'nav.process': 'Processes',
+  'nav.network': 'Network',
+  'nav.disk': 'Disks',
+  'nav.port': 'Ports',
+  'app.optimize': 'Optimize',
+  'app.optimizing': 'Optimizing...',
+  'app.optimize_done': 'Freed {mb} MB of memory',
+  'app.optimize_failed': 'Memory optimization failed',
+  'app.stat_mem': 'MEM',
+  'app.language': 'Switch language',
+  'app.language_failed': 'Failed to switch language',
+  'update.tip': 'Version v{version} is available, click to download',
+  'update.title': 'Update available',
+  'update.message': 'v{current} → v{latest}, click to view the release',
+  'update.latest': 'You are up to date',
+  'app.privacy_on': 'Privacy mode is on: no outbound requests (click to turn off)',
+  'app.privacy_off': 'Turn on privacy mode: no public IP lookup, online geolocation or update checks',
+  'app.privacy_enabled': 'Privacy mode on',
+  'app.privacy_disabled': 'Privacy mode off',
+  'app.migration_failed': 'Data format upgrade failed',
+  'app.migration_failed_message': '{error}. Existing data is untouched; cleaning and memory optimization history is paused and the upgrade will retry on next start. Data folder: {path}',
+  'update.privacy': 'Update checks are off in privacy mode',
+  'budget.all_ifaces': 'All adapters',
+  'budget.exhausted': 'Data budget used up',
+  'budget.used': '{percent}% of data budget used',
+  'budget.message': '{iface}: {used} / {budget}, cycle ends {end}',
+  'portwatch.occupied': 'Port {port} is now in use',
+  'portwatch.freed': 'Port {port} has been freed',
+  'portwatch.changed': 'Port {port} changed owner',
+
+  // Junk cleaner
+  'cleaner.title': 'Junk Cleaner',
+  'cleaner.subtitle': 'Scan for and remove junk files to free up disk space',
+  'cleaner.scan': 'Scan',
+  'cleaner.scanning': 'Scanning...',
+  'cleaner.clean_selected': 'Clean selected ({size})',
+  'cleaner.cleaning': 'Cleaning...',
+  'cleaner.category_count': '{count} categories',
+  'cleaner.recommended': 'Recommended',
+  'cleaner.files': '{count} files',
+  'cleaner.search': 'Search files...',
+  'cleaner.empty': 'Click Scan above to look for junk files',
+  'cleaner.freed': 'Space freed: ',
+  'cleaner.result_sub': '{cleaned} files cleaned, {failed} skipped',
+  'cleaner.in_use_title': 'Files in use ({count})',
+  'cleaner.retry': 'Retry after closing programs',
+  'cleaner.retrying': 'Retrying...',
+  'cleaner.no_holder': 'No locking process found',
+  'cleaner.kill': 'End',
+  'cleaner.history': 'Clean history',
+  'cleaner.never_cleaned': 'Never cleaned',
+  'cleaner.since_last': 'Since last clean',
+  'cleaner.total_freed': 'Total freed',
+  'cleaner.total_count': 'Total files',
+  'cleaner.last_30_days': 'Last 30 days',
+  'cleaner.monthly': 'Monthly',
+  'cleaner.chart_freed': 'Freed (MB)',
+  'cleaner.chart_count': 'Files',
+  'cleaner.chart_files': 'Files',
+  'cleaner.scan_done': 'Scan complete, {count} categories found',
+  'cleaner.scan_failed': 'Scan failed',
+  'cleaner.confirm_clean': 'Clean the {count} selected categories?',
+  'cleaner.confirm_clean_title': 'Confirm',
+  'cleaner.high_risk_title': 'Confirm high-risk categories',
+  'cleaner.high_risk_confirm': 'I understand, continue',
+  'cleaner.cancel': 'Cancel',
+  'cleaner.clean_done': 'Clean complete',
+  'cleaner.clean_failed': 'Clean failed',
+  'cleaner.confirm_kill': 'End process {name} (PID {pid})? Unsaved data may be lost.',
+  'cleaner.kill_title': 'End process',
+  'cleaner.kill_done': 'Process ended',
+  'cleaner.kill_failed': 'Failed to end process',
+  'cleaner.retry_done': 'Retry complete, {count} files cleaned',
+  'cleaner.retry_failed': 'Retry failed',
+  'risk.low': 'Low risk',
+  'risk.medium': 'Medium risk',
+  'risk.high': 'High risk',
+
+  // Overview
+  'dashboard.title': 'Overview',
+  'dashboard.subtitle': 'Live view of your system resources',
+  'dashboard.mem': 'Memory',
+  'dashboard.disk': 'Disk',
+  'dashboard.gpu': 'Graphics',
+  'dashboard.gpu_none_tag': 'Not detected',
+  'dashboard.vram': 'VRAM',
+  'dashboard.shared_mem': 'Shared memory',
+  'dashboard.driver': 'Driver',
+  'dashboard.resolution': 'Resolution',
+  'dashboard.gpu_none': 'No discrete or integrated graphics detected',
+  'dashboard.system': 'System',
+  'dashboard.hostname': 'Host name',
+  'dashboard.os': 'Operating system',
+  'dashboard.ip_privacy': 'Not queried in privacy mode',
+  'dashboard.ip_loading': 'Loading...',
+  'dashboard.public_ip': 'Public IP',
+  'dashboard.location': 'Location',
+  'dashboard.operator': 'ISP',
+  'dashboard.refreshing': 'Refreshing...',
+  'dashboard.refresh': 'Refresh',
+  'dashboard.load_failed': 'Failed to load system info',
+
+  // Disks
+  'disk.title': 'Disks',
+  'disk.subtitle': 'Partitions and large file scan',
+  'disk.used': 'Used',
+  'disk.free': 'Free',
+  'disk.total': 'Total',
+  'disk.compare': 'Space by partition',
+  'disk.share': 'Usage share',
+  'disk.large_files': 'Large files',
+  'disk.search': 'Search files...',
+  'disk.scan': 'Scan',
+  'disk.col_path': 'Path',
+  'disk.col_size': 'Size',
+  'disk.col_type': 'Type',
+  'disk.found': '{count} large files found',
+
+  // Processes
+  'process.title': 'Processes',
+  'process.subtitle': 'Manage running processes and their resource usage',
+  'process.refreshing': 'Refreshing...',
+  'process.refresh': 'Refresh',
+  'process.search': 'Search processes...',
+  'process.count': '{count} processes',
+  'process.col_name': 'Name',
+  'process.col_mem': 'Memory',
+  'process.col_user': 'User',
+  'process.col_op': 'Action',
+  'process.kill': 'End',
+  'process.load_failed': 'Failed to load processes',
+  'process.confirm_kill': 'End process "{name}" (PID: {pid})?',
+  'process.kill_title': 'End process',
+  'process.killed': 'Ended {name}',
+  'process.kill_failed': 'Failed to end the process; administrator rights may be required',
+  'process.protected_title': 'Protected process',
+  'process.kill_anyway': 'End anyway',
+  'process.cancel': 'Cancel',
+
+  // Memory
+  'memory.title': 'Memory',
+  'memory.subtitle': 'Trim process working sets to free physical memory',
+  'memory.usage': 'Memory usage',
+  'memory.used': 'Used',
+  'memory.free': 'Free',
+  'memory.total': 'Total',
+  'memory.tip': 'Safely trims every working set without closing any program',
+  'memory.freed': 'Freed ',
+  'memory.before_after': 'Before {before}% → after {after}%',
+  'memory.total_count': 'Optimizations',
+  'memory.total_freed': 'Total freed',
+  'memory.none': 'None yet',
+  'memory.since_last': 'Since last run',
+  'memory.last_time': 'Last run',
+  'memory.trend': 'Trend',
+  'memory.daily': 'Freed per day',
+  'memory.last_30_days': 'Last 30 days',
+  'memory.monthly': 'Monthly',
+  'memory.compare': 'Recent runs',
+  'memory.last_5': 'Last 5',
+  'memory.chart_freed': 'Freed (MB)',
+  'memory.chart_before_pct': 'Before %',
+  'memory.chart_after_pct': 'After %',
+  'memory.chart_count': 'Runs',
+  'memory.chart_count_unit': 'runs',
+  'memory.chart_before': 'Before',
+  'memory.chart_after': 'After',
+
+  // Network
+  'network.title': 'Network',
+  'network.subtitle': 'Live upload/download speed and traffic statistics',
+  'network.live_up': 'Upload',
+  'network.live_down': 'Download',
+  'network.total_sent': 'Total sent',
+  'network.total_recv': 'Total received',
+  'network.trend': 'Traffic trend',
+  'network.last_30_days': '30 days',
+  'network.by_month': 'Monthly',
+  'network.by_year': 'Yearly',
+  'network.ratio': 'Upload / download',
+  'network.daily_compare': 'Daily traffic (last 14 days)',
+  'network.apps': 'Traffic by app',
+  'network.search': 'Search apps...',
+  'network.col_app': 'App',
+  'network.col_procs': 'Processes',
+  'network.col_sent': 'Sent',
+  'network.col_recv': 'Received',
+  'network.col_total': 'Total',
+  'network.conn_history': 'Connection history',
+  'network.by_process': 'By process',
+  'network.by_remote': 'By remote host',
+  'network.outbound_only': 'Outbound only',
+  'network.col_process': 'Process',
+  'network.col_remote': 'Remote host',
+  'network.col_remotes': 'Remote hosts',
+  'network.col_endpoints': 'Endpoints',
+  'network.col_samples': 'Samples',
+  'network.col_first_seen': 'First seen',
+  'network.col_last_seen': 'Last seen',
+  'network.endpoint_sep': ', ',
+  'network.endpoint_more': ' and {n} more',
+  'network.conn_empty': 'No records yet. The connection table is sampled periodically in the background.',
+  'network.geo_databases': 'Geolocation databases: {list}',
+  'network.geo_list_sep': ', ',
+  'network.geo_missing': 'No geolocation database found. Put a GeoLite2 or DB-IP lite .mmdb file in the data directory to show locations offline.',
+  'network.geo_online': 'Allow manual online lookup (ip-api.com, unencrypted)',
+  'network.geo_online_hint': 'Click "Look up online" for an IP that is not in the local database. The free ip-api.com endpoint only supports HTTP, so the IP is sent in plain text. IPs are never looked up in bulk.',
+  'network.geo_lookup': 'Look up online',
+  'network.geo_not_found': 'No location found for {ip}',
+  'network.inbound': 'Inbound',
+  'network.upload': 'Upload',
+  'network.download': 'Download',
+
+  // Ports
+  'port.title': 'Ports',
+  'port.subtitle': 'Listening ports and their processes',
+  'port.loading': 'Loading...',
+  'port.refresh': 'Refresh',
+  'port.search': 'Port number...',
+  'port.query': 'Search',
+  'port.kill_all': 'Kill all',
+  'port.watch': 'Watch port',
+  'port.find_free': 'Free ports',
+  'port.forward': 'Forward',
+  'port.fingerprinting': 'Detecting...',
+  'port.fingerprint': 'Detect services',
+  'port.count': '{n} ports',
+  'port.query_info': '{n} processes found on port {port}',
+  'port.watch_free': 'Free',
+  'port.watch_history': 'History {n}',
+  'port.unwatch': 'Unwatch',
+  'port.forward_persist': 'Auto-restore',
+  'port.forward_stat': 'Connections {active}/{total} · ↑ {in} · ↓ {out}',
+  'port.forward_stop': 'Stop and remove',
+  'port.col_listen': 'Listen address',
+  'port.col_port': 'Port',
+  'port.col_service': 'Service',
+  'port.col_proto': 'Protocol',
+  'port.col_remote': 'Remote address',
+  'port.col_status': 'State',
+  'port.col_name': 'Process',
+  'port.col_op': 'Action',
+  'port.kill': 'Kill',
+  'port.empty': 'No listening ports',
+  'port.loading_ports': 'Loading ports...',
+  'port.load_failed': 'Failed to load ports',
+  'port.invalid_port': 'Enter a port between 1 and 65535',
+  'port.querying': 'Querying port {port}...',
+  'port.no_process': 'No process is listening on port {port}',
+  'port.query_failed': 'Failed to query port',
+  'port.watch_prompt': 'Port to watch',
+  'port.watch_invalid': 'Enter a port number',
+  'port.watch_label_prompt': 'Note for port {port} (optional)',
+  'port.watched': 'Watching port {port}; you will be notified when it is taken or released',
+  'port.free_prompt': 'Port range, e.g. 8000-8100',
+  'port.free_title': 'Find free ports',
+  'port.free_invalid': 'Use start-end',
+  'port.free_found': 'Free ports: {ports}',
+  'port.free_none': 'No free port in range',
+  'port.free_skipped': 'Skipped {in_use} in use and {reserved} reserved',
+  'port.free_excluded': 'Reserved ({proto}): {start}-{end}',
+  'port.range_title': 'Ports {start}-{end}',
+  'port.service_unknown': 'Unknown',
+  'port.fp_title': 'Title: {v}',
+  'port.fp_version': 'Version: {v}',
+  'port.fp_banner': 'Banner: {v}',
+  'port.fp_subject': 'Certificate: {v}',
+  'port.fp_issuer': 'Issuer: {v}',
+  'port.fp_expiry': 'Expires: {v}',
+  'port.fp_expired': ' (expired)',
+  'port.forward_prompt': 'Enter listen -> target, e.g. 0.0.0.0:8080 -> 127.0.0.1:3000',
+  'port.forward_title': 'New port forward',
+  'port.forward_invalid': 'Use host:port -> host:port',
+  'port.forward_persist_prompt': 'Restore this forward on next start?',
+  'port.forward_once': 'This session only',
+  'port.forwarded': 'Forwarding {listen} → {target}',
+  'port.history_now': 'now',
+  'port.history_empty': 'No records',
+  'port.status_killed': 'Killed',
+  'port.status_already_exited': 'Already exited',
+  'port.status_access_denied': 'Access denied',
+  'port.status_protected': 'Protected, not killed',
+  'port.status_failed': 'Failed',
+  'port.killed': 'Killed {n} processes on port {port}',
+  'port.forced': ' (forced)',
+  'port.port_title': 'Port {port}',
+  'port.kill_confirm': 'Kill process "{name}" (PID: {pid})?',
+  'port.kill_title': 'Kill process',
+  'port.kill_failed': 'Failed to kill the process, possibly due to insufficient permissions',
+  'port.kill_all_confirm': 'Kill every process on port {port}?\n{pids}',
+  'port.kill_all_title': 'Kill processes',
+} as Record<string, string>
@@ -0,0 +1,32 @@
+import { ref } from 'vue'
+import zhCN from './zh-CN'
+import enUS from './en-US'
+
+export type Locale = 'zh-CN' | 'en-US'
+
+// 与后端 internal/i18n 的语言代码一致
+const messages: Record<Locale, Record<string, string>> = {
+  'zh-CN': zhCN,
+  'en-US': enUS,
+}
+
+export const localeNames: Record<Locale, string> = {
+  'zh-CN': '中文',
+  'en-US': 'English',
+}
+
+export const locale = ref<Locale>('zh-CN')
+
+export const setLocale = (l: string) => {
+  if (l in messages) {
+    locale.value = l as Locale
+    document.documentElement.lang = l
+  }
+}
+
+// t 取当前语言的文字，{name} 占位符按 params 替换；缺失时回退到中文，再回退到 key
+export const t = (key: string, params?: Record<string, string | number>): string => {
+  const msg = messages[locale.value][key] ?? messages['zh-CN'][key] ?? key
+  if (!params) return msg
+  return msg.replace(/\{(\w+)\}/g, (m, name) => (name in params ? String(params[name]) : m))
+}
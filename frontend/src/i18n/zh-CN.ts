@@ -0,0 +1,298 @@
+export default {
+  // 侧边栏
+  'app.subtitle': '系统清理工具',
+  'nav.dashboard': '系统概览',
+  'nav.cleaner': '垃圾清理',
+  'nav.memory': '内存优化',
+  'nav.process': '进程管理',
+  'nav.network': '流量监控',
+  'nav.disk': '磁盘管理',
+  'nav.port': '端口管理',
+  'app.optimize': '一键优化',
+  'app.optimizing': '优化中...',
+  'app.optimize_done': '释放了 {mb} MB 内存',
+  'app.optimize_failed': '内存优化失败',
+  'app.stat_mem': '内存',
+  'app.language': '切换语言',
+  'app.language_failed': '切换语言失败',
+  'update.tip': '发现新版本 v{version}，点击前往下载',
+  'update.title': '发现新版本',
+  'update.message': 'v{current} → v{latest}，点击查看更新',
+  'update.latest': '已是最新版本',
+  'app.privacy_on': '隐私模式已开启：不发出任何外网请求（点击关闭）',
+  'app.privacy_off': '开启隐私模式：不查询公网 IP、不在线查询归属地、不检查更新',
+  'app.privacy_enabled': '已开启隐私模式',
+  'app.privacy_disabled': '已关闭隐私模式',
+  'app.migration_failed': '数据格式升级失败',
+  'app.migration_failed_message': '{error}。旧数据未改动，清理和内存优化历史暂停记录，下次启动时重试。数据目录：{path}',
+  'update.privacy': '隐私模式下不检查更新',
+  'budget.all_ifaces': '全部网卡',
+  'budget.exhausted': '流量套餐已用完',
+  'budget.used': '流量已用 {percent}%',
+  'budget.message': '{iface}：{used} / {budget}，本周期至 {end}',
+  'portwatch.occupied': '端口 {port} 已被占用',
+  'portwatch.freed': '端口 {port} 已释放',
+  'portwatch.changed': '端口 {port} 的占用进程已变化',
+
+  // 垃圾清理
+  'cleaner.title': '垃圾清理',
+  'cleaner.subtitle': '扫描并清理系统垃圾文件，释放磁盘空间',
+  'cleaner.scan': '扫描垃圾',
+  'cleaner.scanning': '扫描中...',
+  'cleaner.clean_selected': '清理选中 ({size})',
+  'cleaner.cleaning': '清理中...',
+  'cleaner.category_count': '共 {count} 个分类',
+  'cleaner.recommended': '推荐',
+  'cleaner.files': '{count} 个文件',
+  'cleaner.search': '搜索文件...',
+  'cleaner.empty': '点击上方扫描按钮开始检测垃圾文件',
+  'cleaner.freed': '释放空间: ',
+  'cleaner.result_sub': '成功清理 {cleaned} 个文件，{failed} 个跳过',
+  'cleaner.in_use_title': '被占用的文件（{count}）',
+  'cleaner.retry': '关闭程序后重试',
+  'cleaner.retrying': '重试中...',
+  'cleaner.no_holder': '未找到占用进程',
+  'cleaner.kill': '结束',
+  'cleaner.history': '清理历史',
+  'cleaner.never_cleaned': '从未清理',
+  'cleaner.since_last': '距上次清理',
+  'cleaner.total_freed': '累计释放',
+  'cleaner.total_count': '累计文件数',
+  'cleaner.last_30_days': '近30天',
+  'cleaner.monthly': '按月',
+  'cleaner.chart_freed': '释放(MB)',
+  'cleaner.chart_count': '文件数',
+  'cleaner.chart_files': '文件',
+  'cleaner.scan_done': '扫描完成，发现 {count} 个分类',
+  'cleaner.scan_failed': '扫描失败',
+  'cleaner.confirm_clean': '确定清理选中的 {count} 个分类？',
+  'cleaner.confirm_clean_title': '确认清理',
+  'cleaner.high_risk_title': '高风险分类二次确认',
+  'cleaner.high_risk_confirm': '我已了解，继续清理',
+  'cleaner.cancel': '取消',
+  'cleaner.clean_done': '清理完成',
+  'cleaner.clean_failed': '清理失败',
+  'cleaner.confirm_kill': '确定结束进程 {name} (PID {pid})？未保存的数据可能会丢失。',
+  'cleaner.kill_title': '结束进程',
+  'cleaner.kill_done': '进程已结束',
+  'cleaner.kill_failed': '结束进程失败',
+  'cleaner.retry_done': '重试完成，清理 {count} 个文件',
+  'cleaner.retry_failed': '重试失败',
+  'risk.low': '低风险',
+  'risk.medium': '中风险',
+  'risk.high': '高风险',
+
+  // 系统概览
+  'dashboard.title': '系统概览',
+  'dashboard.subtitle': '实时监控您的系统资源状态',
+  'dashboard.mem': '内存',
+  'dashboard.disk': '磁盘',
+  'dashboard.gpu': '显卡信息',
+  'dashboard.gpu_none_tag': '未检测到',
+  'dashboard.vram': '显存',
+  'dashboard.shared_mem': '共享内存',
+  'dashboard.driver': '驱动',
+  'dashboard.resolution': '分辨率',
+  'dashboard.gpu_none': '未检测到独立显卡或核显',
+  'dashboard.system': '系统信息',
+  'dashboard.hostname': '主机名',
+  'dashboard.os': '操作系统',
+  'dashboard.ip_privacy': '隐私模式，不查询',
+  'dashboard.ip_loading': '获取中...',
+  'dashboard.public_ip': '公网 IP',
+  'dashboard.location': '归属地',
+  'dashboard.operator': '运营商',
+  'dashboard.refreshing': '刷新中...',
+  'dashboard.refresh': '刷新数据',
+  'dashboard.load_failed': '获取系统信息失败',
+
+  // 磁盘管理
+  'disk.title': '磁盘管理',
+  'disk.subtitle': '查看磁盘分区及大文件扫描',
+  'disk.used': '已用',
+  'disk.free': '可用',
+  'disk.total': '总量',
+  'disk.compare': '分区空间对比',
+  'disk.share': '空间占比',
+  'disk.large_files': '大文件扫描',
+  'disk.search': '搜索文件...',
+  'disk.scan': '扫描',
+  'disk.col_path': '文件路径',
+  'disk.col_size': '大小',
+  'disk.col_type': '类型',
+  'disk.found': '共找到 {count} 个大文件',
+
+  // 进程管理
+  'process.title': '进程管理',
+  'process.subtitle': '管理系统运行中的进程，查看资源占用',
+  'process.refreshing': '刷新中...',
+  'process.refresh': '刷新',
+  'process.search': '搜索进程名...',
+  'process.count': '{count} 个进程',
+  'process.col_name': '进程名',
+  'process.col_mem': '内存',
+  'process.col_user': '用户',
+  'process.col_op': '操作',
+  'process.kill': '结束',
+  'process.load_failed': '获取进程列表失败',
+  'process.confirm_kill': '确定结束进程 "{name}" (PID: {pid})？',
+  'process.kill_title': '结束进程',
+  'process.killed': '已结束进程 {name}',
+  'process.kill_failed': '结束进程失败，可能权限不足',
+  'process.protected_title': '受保护的进程',
+  'process.kill_anyway': '仍然结束',
+  'process.cancel': '取消',
+
+  // 内存优化
+  'memory.title': '内存优化',
+  'memory.subtitle': '智能收缩进程工作集，释放物理内存',
+  'memory.usage': '内存占用',
+  'memory.used': '已用',
+  'memory.free': '可用',
+  'memory.total': '总量',
+  'memory.tip': '安全收缩所有进程工作集，不关闭任何程序',
+  'memory.freed': '本次释放 ',
+  'memory.before_after': '优化前 {before}% → 优化后 {after}%',
+  'memory.total_count': '累计优化次数',
+  'memory.total_freed': '累计释放内存',
+  'memory.none': '暂无',
+  'memory.since_last': '距上次优化',
+  'memory.last_time': '上次优化时间',
+  'memory.trend': '优化趋势',
+  'memory.daily': '每日释放量',
+  'memory.last_30_days': '近 30 天',
+  'memory.monthly': '月度统计',
+  'memory.compare': '最近对比',
+  'memory.last_5': '最近 5 次',
+  'memory.chart_freed': '释放(MB)',
+  'memory.chart_before_pct': '优化前%',
+  'memory.chart_after_pct': '优化后%',
+  'memory.chart_count': '次数',
+  'memory.chart_count_unit': '次',
+  'memory.chart_before': '优化前',
+  'memory.chart_after': '优化后',
+
+  // 流量监控
+  'network.title': '流量监控',
+  'network.subtitle': '实时监控网络上传下载速度及流量统计',
+  'network.live_up': '实时上传',
+  'network.live_down': '实时下载',
+  'network.total_sent': '历史总发送',
+  'network.total_recv': '历史总接收',
+  'network.trend': '流量趋势',
+  'network.last_30_days': '近30天',
+  'network.by_month': '按月',
+  'network.by_year': '按年',
+  'network.ratio': '上传/下载占比',
+  'network.daily_compare': '每日流量对比（近 14 天）',
+  'network.apps': '应用流量监控',
+  'network.search': '搜索应用...',
+  'network.col_app': '应用名称',
+  'network.col_procs': '进程数',
+  'network.col_sent': '发送',
+  'network.col_recv': '接收',
+  'network.col_total': '总计',
+  'network.conn_history': '连接历史',
+  'network.by_process': '按进程',
+  'network.by_remote': '按远端主机',
+  'network.outbound_only': '只看出站',
+  'network.col_process': '进程',
+  'network.col_remote': '远端主机',
+  'network.col_remotes': '远端主机数',
+  'network.col_endpoints': '端点',
+  'network.col_samples': '采样次数',
+  'network.col_first_seen': '首次出现',
+  'network.col_last_seen': '最近出现',
+  'network.endpoint_sep': '，',
+  'network.endpoint_more': ' 等 {n} 个',
+  'network.conn_empty': '暂无记录，后台每隔一段时间采样一次连接表',
+  'network.geo_databases': '归属地数据库：{list}',
+  'network.geo_list_sep': '、',
+  'network.geo_missing': '未找到归属地数据库，将 GeoLite2 或 DB-IP lite 的 .mmdb 文件放入数据目录即可离线显示归属地',
+  'network.geo_online': '允许手动在线查询（ip-api.com，未加密）',
+  'network.geo_online_hint': '本地查不到时可点击“在线查询”逐个查询；ip-api.com 免费接口只支持 HTTP，查询的 IP 会以明文发送，不会批量查询',
+  'network.geo_lookup': '在线查询',
+  'network.geo_not_found': '未查到 {ip} 的归属地',
+  'network.inbound': '入站',
+  'network.upload': '上传',
+  'network.download': '下载',
+
+  // 端口管理
+  'port.title': '端口管理',
+  'port.subtitle': '查看监听端口及关联进程',
+  'port.loading': '加载中...',
+  'port.refresh': '刷新',
+  'port.search': '输入端口号...',
+  'port.query': '查询',
+  'port.kill_all': '一键结束',
+  'port.watch': '关注端口',
+  'port.find_free': '空闲端口',
+  'port.forward': '端口转发',
+  'port.fingerprinting': '识别中...',
+  'port.fingerprint': '识别服务',
+  'port.count': '{n} 个端口',
+  'port.query_info': '端口 {port} 共找到 {n} 个进程',
+  'port.watch_free': '空闲',
+  'port.watch_history': '历史 {n}',
+  'port.unwatch': '取消关注',
+  'port.forward_persist': '自动恢复',
+  'port.forward_stat': '连接 {active}/{total} · ↑ {in} · ↓ {out}',
+  'port.forward_stop': '停止并删除',
+  'port.col_listen': '监听地址',
+  'port.col_port': '端口',
+  'port.col_service': '服务',
+  'port.col_proto': '协议',
+  'port.col_remote': '远端地址',
+  'port.col_status': '状态',
+  'port.col_name': '进程名',
+  'port.col_op': '操作',
+  'port.kill': '结束',
+  'port.empty': '暂无监听中的端口',
+  'port.loading_ports': '正在加载端口...',
+  'port.load_failed': '加载端口列表失败',
+  'port.invalid_port': '请输入 1-65535 之间的端口号',
+  'port.querying': '正在查询端口 {port}...',
+  'port.no_process': '端口 {port} 没有监听中的进程',
+  'port.query_failed': '查询端口失败',
+  'port.watch_prompt': '输入要关注的端口号',
+  'port.watch_invalid': '请输入端口号',
+  'port.watch_label_prompt': '端口 {port} 的备注（可留空）',
+  'port.watched': '已关注端口 {port}，占用或释放时会通知',
+  'port.free_prompt': '输入端口范围，如 8000-8100',
+  'port.free_title': '查找空闲端口',
+  'port.free_invalid': '格式为 起始-结束',
+  'port.free_found': '可用端口：{ports}',
+  'port.free_none': '范围内没有可用端口',
+  'port.free_skipped': '已占用跳过 {in_use} 个，系统保留跳过 {reserved} 个',
+  'port.free_excluded': '保留范围（{proto}）：{start}-{end}',
+  'port.range_title': '端口 {start}-{end}',
+  'port.service_unknown': '未知',
+  'port.fp_title': '标题: {v}',
+  'port.fp_version': '版本: {v}',
+  'port.fp_banner': '欢迎信息: {v}',
+  'port.fp_subject': '证书: {v}',
+  'port.fp_issuer': '颁发者: {v}',
+  'port.fp_expiry': '到期: {v}',
+  'port.fp_expired': '（已过期）',
+  'port.forward_prompt': '输入 监听地址 -> 目标地址，如 0.0.0.0:8080 -> 127.0.0.1:3000',
+  'port.forward_title': '新建端口转发',
+  'port.forward_invalid': '格式为 主机:端口 -> 主机:端口',
+  'port.forward_persist_prompt': '下次启动时是否自动恢复这条转发？',
+  'port.forward_once': '仅本次',
+  'port.forwarded': '已转发 {listen} → {target}',
+  'port.history_now': '至今',
+  'port.history_empty': '暂无记录',
+  'port.status_killed': '已结束',
+  'port.status_already_exited': '已退出',
+  'port.status_access_denied': '权限不足',
+  'port.status_protected': '受保护，未结束',
+  'port.status_failed': '结束失败',
+  'port.killed': '端口 {port} 已结束 {n} 个进程',
+  'port.forced': '（强制）',
+  'port.port_title': '端口 {port}',
+  'port.kill_confirm': '确定结束进程 "{name}" (PID: {pid})？',
+  'port.kill_title': '结束进程',
+  'port.kill_failed': '结束进程失败，可能权限不足',
+  'port.kill_all_confirm': '确定结束端口 {port} 下的所有进程？\n{pids}',
+  'port.kill_all_title': '批量结束进程',
+} as Record<string, string>
@@ -3,6 +3,7 @@ module win-cleaner
 go 1.24
 
 require (
+	github.com/oschwald/maxminddb-golang v1.13.1
 	github.com/shirou/gopsutil/v3 v3.24.1
 	github.com/wailsapp/wails/v2 v2.11.0
 	golang.org/x/sys v0.30.0
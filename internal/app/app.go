@@ -3,16 +3,24 @@ package app
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
+	"win-cleaner/internal/backup"
 	"win-cleaner/internal/cleaner"
+	"win-cleaner/internal/i18n"
 	"win-cleaner/internal/memory"
+	"win-cleaner/internal/migration"
 	"win-cleaner/internal/model"
 	"win-cleaner/internal/monitor"
+	"win-cleaner/internal/publicip"
+	"win-cleaner/internal/settings"
+	"win-cleaner/pkg/datadir"
 	"win-cleaner/pkg/winapi"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // AppVersion 当前应用版本（构建时通过 -ldflags 注入，默认 dev）
@@ -20,42 +28,173 @@ var AppVersion = "0.2.0"
 
 // App Wails 应用主结构
 type App struct {
-	ctx         context.Context
-	scanResults []model.ScanResult
-	stopSampler chan struct{}
+	ctx            context.Context
+	scanResults    []model.ScanResult
+	stopSampler    chan struct{}
+	samplerChanged chan time.Duration // 采样间隔变更
+	watchChanged   chan time.Duration // 端口关注轮询间隔变更
+	connChanged    chan time.Duration // 连接历史采样间隔变更
+}
+
+// codedError 带错误代码的错误，前端按代码区分处理
+type codedError interface {
+	error
+	ErrorCode() string
+}
+
+// FormatError 把带代码的错误转换为 model.AppError 传给前端，其余错误保持为字符串
+func FormatError(err error) any {
+	err = LocalizeError(err)
+	var ce codedError
+	if errors.As(err, &ce) {
+		return model.AppError{Code: ce.ErrorCode(), Message: err.Error(), Data: ce}
+	}
+	return err.Error()
 }
 
 func NewApp() *App {
 	return &App{
-		stopSampler: make(chan struct{}),
+		stopSampler:    make(chan struct{}),
+		samplerChanged: make(chan time.Duration, 1),
+		watchChanged:   make(chan time.Duration, 1),
+		connChanged:    make(chan time.Duration, 1),
 	}
 }
 
 func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
-	// 启动流量采样协程（每 30 秒采样一次）
+	// 升级数据目录格式（失败时保留原数据并暂停写入历史，下次启动重试；原因见 GetDataDirInfo）
+	_ = migration.Run()
+	_ = i18n.SetLocale(settings.Get().Locale)
+	settings.OnChange(a.onSettingsChanged)
+	// 启动流量采样协程（间隔见设置，默认 30 秒）
 	go a.netSamplerLoop()
+	go a.portWatchLoop()
+	go a.connHistoryLoop()
+	// 恢复已保存的端口转发（失败的在列表中显示原因）
+	_ = monitor.RestorePortForwards()
 }
 
 func (a *App) Shutdown(ctx context.Context) {
 	close(a.stopSampler)
+	_ = monitor.FlushConnHistory()
+	monitor.ReleaseAllPorts()
+	monitor.CloseAllPortForwards()
 }
 
 func (a *App) netSamplerLoop() {
 	// 初始化第一次采样基准
 	monitor.RecordNetTrafficSample()
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(sampleInterval(settings.Get()))
 	defer ticker.Stop()
+	lastBudgetCheck := time.Now()
 	for {
 		select {
 		case <-ticker.C:
 			monitor.RecordNetTrafficSample()
+			// 每 5 分钟检查一次流量套餐
+			if time.Since(lastBudgetCheck) >= budgetCheckInterval {
+				lastBudgetCheck = time.Now()
+				a.checkNetBudgets()
+			}
+		case d := <-a.samplerChanged:
+			ticker.Reset(d)
+		case <-a.stopSampler:
+			return
+		}
+	}
+}
+
+// portWatchLoop 轮询关注端口，占用或释放时通过 "port:watch" 事件通知前端
+func (a *App) portWatchLoop() {
+	ticker := time.NewTicker(portWatchInterval(settings.Get()))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !monitor.HasWatchedPorts() {
+				continue
+			}
+			events, _ := monitor.PollPortWatch()
+			for _, e := range events {
+				runtime.EventsEmit(a.ctx, "port:watch", e)
+			}
+		case d := <-a.watchChanged:
+			ticker.Reset(d)
 		case <-a.stopSampler:
 			return
 		}
 	}
 }
 
+// connHistoryLoop 定时采样连接表，记录各进程访问过的远端地址
+func (a *App) connHistoryLoop() {
+	ticker := time.NewTicker(connSampleInterval(settings.Get()))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = monitor.RecordConnSample()
+		case d := <-a.connChanged:
+			ticker.Reset(d)
+		case <-a.stopSampler:
+			return
+		}
+	}
+}
+
+// budgetCheckInterval 流量套餐检查间隔
+const budgetCheckInterval = 5 * time.Minute
+
+func sampleInterval(s model.Settings) time.Duration {
+	return time.Duration(s.NetSampleInterval) * time.Second
+}
+
+func portWatchInterval(s model.Settings) time.Duration {
+	return time.Duration(s.PortWatchInterval) * time.Second
+}
+
+func connSampleInterval(s model.Settings) time.Duration {
+	return time.Duration(s.ConnSampleInterval) * time.Second
+}
+
+// resetTicker 通知轮询协程使用新的间隔，只保留最新的值
+func resetTicker(ch chan time.Duration, d time.Duration) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- d
+}
+
+// onSettingsChanged 设置变更后切换界面语言、调整采样和轮询间隔，并通知前端
+func (a *App) onSettingsChanged(old, cur model.Settings) {
+	if old.Locale != cur.Locale {
+		_ = i18n.SetLocale(cur.Locale)
+	}
+	if old.NetSampleInterval != cur.NetSampleInterval {
+		resetTicker(a.samplerChanged, sampleInterval(cur))
+	}
+	if old.PortWatchInterval != cur.PortWatchInterval {
+		resetTicker(a.watchChanged, portWatchInterval(cur))
+	}
+	if old.ConnSampleInterval != cur.ConnSampleInterval {
+		resetTicker(a.connChanged, connSampleInterval(cur))
+	}
+	runtime.EventsEmit(a.ctx, "settings:changed", cur)
+}
+
+// checkNetBudgets 流量套餐达到 50/80/100% 时通知前端
+func (a *App) checkNetBudgets() {
+	alerts, err := monitor.CheckNetBudgetAlerts()
+	if err != nil {
+		return
+	}
+	for _, alert := range alerts {
+		runtime.EventsEmit(a.ctx, "net:budget-alert", alert)
+	}
+}
+
 // GetSystemInfo 获取系统信息
 func (a *App) GetSystemInfo() (*model.SystemInfo, error) {
 	return monitor.GetSystemInfo()
@@ -65,22 +204,39 @@ func (a *App) GetSystemInfo() (*model.SystemInfo, error) {
 func (a *App) ScanJunk() []model.ScanResult {
 	categories := cleaner.DefaultCategories()
 	a.scanResults = cleaner.Scan(categories)
+	// 保存扫描摘要，用于与下次扫描对比
+	_, _ = cleaner.SaveScanSnapshot(categories, a.scanResults)
 	return a.scanResults
 }
 
-// CleanJunk 清理垃圾文件（传入要清理的分类名列表）
-func (a *App) CleanJunk(categoryNames []string) model.CleanResult {
+// ListScanSnapshots 列出历史扫描摘要（kind: "junk" / "large"，为空返回全部）
+func (a *App) ListScanSnapshots(kind string) ([]model.ScanSnapshot, error) {
+	return cleaner.ListScanSnapshots(kind)
+}
+
+// CompareScans 对比两次扫描（ID 为空时默认对比最近两次）
+func (a *App) CompareScans(oldID, newID string) (*model.ScanDiff, error) {
+	return cleaner.CompareScanSnapshots(oldID, newID)
+}
+
+// GetCleanRecommendation 根据最近一次扫描结果给出推荐清理的分类
+func (a *App) GetCleanRecommendation() (*model.CleanRecommendation, error) {
+	return cleaner.Recommend(a.scanResults)
+}
+
+// CleanJunk 清理垃圾文件（传入要清理的分类 ID 列表）
+func (a *App) CleanJunk(categoryIDs []string) model.CleanResult {
 	// 收集选中分类的所有文件
 	var items []model.JunkItem
-	nameSet := make(map[string]bool)
-	for _, name := range categoryNames {
-		nameSet[name] = true
+	idSet := make(map[string]bool)
+	for _, id := range categoryIDs {
+		idSet[id] = true
 	}
 
 	hasRecycleBin := false
 	for _, result := range a.scanResults {
-		if nameSet[result.Category] {
-			if result.Category == "回收站" {
+		if idSet[result.Category] {
+			if result.Category == cleaner.CategoryRecycleBin {
 				hasRecycleBin = true
 			} else {
 				items = append(items, result.Items...)
@@ -95,21 +251,60 @@ func (a *App) CleanJunk(categoryNames []string) model.CleanResult {
 		if err := winapi.EmptyRecycleBin(); err == nil {
 			// 获取清空前的大小
 			for _, r := range a.scanResults {
-				if r.Category == "回收站" {
+				if r.Category == cleaner.CategoryRecycleBin {
 					result.FreedSize += r.Size
 					result.CleanedCount += r.Count
+					result.Categories = append(result.Categories, model.CategoryFreed{
+						Category:     r.Category,
+						CategoryName: r.CategoryName,
+						FreedSize:    r.Size,
+						CleanedCount: r.Count,
+					})
 					break
 				}
 			}
 		}
 	}
 
+	attachFileLockers(&result)
+
 	// 记录清理历史
 	_ = cleaner.RecordClean(result)
 
 	return result
 }
 
+// maxLockerLookups 单次清理最多查询占用进程的文件数（每次查询需要建立 Restart Manager 会话）
+const maxLockerLookups = 50
+
+// attachFileLockers 为因占用而失败的文件查询占用进程
+func attachFileLockers(result *model.CleanResult) {
+	looked := 0
+	for i := range result.Failures {
+		f := &result.Failures[i]
+		if !f.InUse || looked >= maxLockerLookups {
+			continue
+		}
+		looked++
+		f.Holders, _ = monitor.FindFileLockers(f.Path)
+	}
+}
+
+// RetryClean 重新清理之前失败的文件（关闭占用程序后调用）
+func (a *App) RetryClean(items []model.JunkItem) model.CleanResult {
+	result := cleaner.Clean(items)
+	attachFileLockers(&result)
+	if result.CleanedCount > 0 {
+		_ = cleaner.RecordClean(result)
+	}
+	return result
+}
+
+// WhoIsUsingFile 查询占用指定文件的进程
+func (a *App) WhoIsUsingFile(path string) ([]model.FileLocker, error) {
+	return monitor.FindFileLockers(path)
+}
+
 // GetCleanHistory 获取清理历史统计
 func (a *App) GetCleanHistory() (*model.CleanHistoryStats, error) {
 	return cleaner.GetCleanHistoryStats()
@@ -131,9 +326,19 @@ func (a *App) GetProcessList() ([]model.ProcessInfo, error) {
 	return monitor.GetProcessList()
 }
 
-// KillProcess 结束进程
-func (a *App) KillProcess(pid int32) error {
-	return monitor.KillProcess(pid)
+// KillProcess 结束进程；关键进程需用户确认后以 override=true 重试
+func (a *App) KillProcess(pid int32, override bool) error {
+	return monitor.KillProcess(pid, override)
+}
+
+// GetProcessProtection 获取进程保护设置（内置关键进程、允许列表、禁止列表）
+func (a *App) GetProcessProtection() (model.ProcessProtection, error) {
+	return monitor.GetProcessProtection()
+}
+
+// SetProcessProtection 保存进程保护的允许列表和禁止列表
+func (a *App) SetProcessProtection(p model.ProcessProtection) error {
+	return monitor.SetProcessProtection(p)
 }
 
 // GetGPUInfo 获取显卡信息
@@ -156,6 +361,31 @@ func (a *App) GetNetTrafficStats() (*model.NetTrafficStats, error) {
 	return monitor.GetNetTrafficStats()
 }
 
+// GetNetInterfaces 获取网卡列表及是否计入流量统计
+func (a *App) GetNetInterfaces() ([]model.NetInterfaceInfo, error) {
+	return monitor.GetNetInterfaces()
+}
+
+// SetNetInterfaceIncluded 设置网卡是否计入流量统计
+func (a *App) SetNetInterfaceIncluded(name string, included bool) error {
+	return monitor.SetNetInterfaceIncluded(name, included)
+}
+
+// GetNetBudgets 获取流量套餐及当前周期用量
+func (a *App) GetNetBudgets() ([]model.NetBudgetStatus, error) {
+	return monitor.GetNetBudgets()
+}
+
+// SetNetBudget 新增或修改流量套餐
+func (a *App) SetNetBudget(budget model.NetBudget) error {
+	return monitor.SetNetBudget(budget)
+}
+
+// DeleteNetBudget 删除流量套餐
+func (a *App) DeleteNetBudget(iface string) error {
+	return monitor.DeleteNetBudget(iface)
+}
+
 // GetDiskList 获取所有磁盘分区信息
 func (a *App) GetDiskList() ([]model.DiskInfo, error) {
 	return monitor.GetDiskList()
@@ -163,13 +393,19 @@ func (a *App) GetDiskList() ([]model.DiskInfo, error) {
 
 // ScanLargeFiles 扫描大文件
 func (a *App) ScanLargeFiles(root string, minSizeMB int64) *model.DiskScanResult {
-	files := monitor.ScanLargeFiles(root, minSizeMB, 100)
+	files := monitor.ScanLargeFiles(root, minSizeMB, settings.Get().LargeFileLimit)
+	_, _ = cleaner.SaveLargeFileSnapshot(root, minSizeMB, files)
 	return &model.DiskScanResult{
 		Files: files,
 		Count: len(files),
 	}
 }
 
+// AnalyzeDownloads 分析下载目录（按类型/时间分组，标记已安装程序的安装包）
+func (a *App) AnalyzeDownloads() (*model.DownloadsReport, error) {
+	return cleaner.AnalyzeDownloads()
+}
+
 // GetMemOptStats 获取内存优化历史统计
 func (a *App) GetMemOptStats() (*model.MemOptStats, error) {
 	return memory.GetMemOptStats()
@@ -182,35 +418,216 @@ func (a *App) GetAppVersion() string {
 
 // GetPortList 获取指定端口的进程列表
 func (a *App) GetPortList(port uint16) ([]model.PortInfo, error) {
-	return monitor.GetPortListSimple(port)
+	return monitor.GetPortList(port)
+}
+
+// GetConnections 获取 TCP/UDP 连接表，可按协议、地址族、状态、PID、端口范围过滤
+func (a *App) GetConnections(filter model.PortFilter) (*model.PortScanResult, error) {
+	return monitor.ListConnections(filter)
+}
+
+// KillProcessesByPort 结束占用指定端口的进程，返回每个进程的处理结果
+func (a *App) KillProcessesByPort(port uint16, opts model.KillPortOptions) (*model.KillPortResult, error) {
+	return monitor.KillProcessesByPort(port, opts)
+}
+
+// GetPortWatchlist 获取关注端口的当前占用情况及占用历史
+func (a *App) GetPortWatchlist() ([]model.PortWatchStatus, error) {
+	return monitor.GetPortWatchStatus()
+}
+
+// AddWatchedPort 关注端口，端口被占用或释放时推送 "port:watch" 事件
+func (a *App) AddWatchedPort(port uint16, label string) error {
+	return monitor.AddWatchedPort(port, label)
+}
+
+// RemoveWatchedPort 取消关注端口
+func (a *App) RemoveWatchedPort(port uint16) error {
+	return monitor.RemoveWatchedPort(port)
+}
+
+// FingerprintPorts 通过回环地址识别端口上运行的服务（HTTP/TLS/Redis/PostgreSQL/MySQL/SSH）
+func (a *App) FingerprintPorts(ports []uint16) []model.ServiceFingerprint {
+	return monitor.FingerprintPorts(ports)
+}
+
+// CreatePortForward 新建 TCP 端口转发 listenAddr → targetAddr，persist 为 true 时下次启动自动恢复
+func (a *App) CreatePortForward(listenAddr, targetAddr string, persist bool) (*model.PortForwardStatus, error) {
+	return monitor.CreatePortForward(listenAddr, targetAddr, persist)
+}
+
+// ListPortForwards 获取端口转发列表及连接数、流量
+func (a *App) ListPortForwards() []model.PortForwardStatus {
+	return monitor.ListPortForwards()
+}
+
+// StopPortForward 停止并删除端口转发
+func (a *App) StopPortForward(id string) error {
+	return monitor.StopPortForward(id)
+}
+
+// QueryConnHistory 按进程或远端主机分组查询连接历史
+func (a *App) QueryConnHistory(q model.ConnHistoryQuery) (*model.ConnHistoryResult, error) {
+	return monitor.QueryConnHistory(q)
+}
+
+// ClearConnHistory 清空连接历史
+func (a *App) ClearConnHistory() error {
+	return monitor.ClearConnHistory()
+}
+
+// LookupGeoIP 批量查询 IP 归属地和 ASN（只查数据目录中的 .mmdb 数据库）
+func (a *App) LookupGeoIP(ips []string) []model.GeoIPInfo {
+	return monitor.LookupGeoIP(ips)
+}
+
+// LookupGeoIPOnline 查询用户点选的单个 IP，本地查不到且设置允许时在线查询
+func (a *App) LookupGeoIPOnline(ip string) model.GeoIPInfo {
+	return monitor.LookupGeoIPOnline(ip)
+}
+
+// GetGeoIPStatus 获取已加载的 GeoIP 数据库
+func (a *App) GetGeoIPStatus() model.GeoIPStatus {
+	return monitor.GetGeoIPStatus()
+}
+
+// FindFreePorts 在端口范围内查找空闲端口，可选择占住直到 ReleasePort
+func (a *App) FindFreePorts(req model.FreePortRequest) (*model.FreePortResult, error) {
+	return monitor.FindFreePorts(req)
+}
+
+// GetHeldPorts 获取当前占住的端口
+func (a *App) GetHeldPorts() []model.HeldPort {
+	return monitor.GetHeldPorts()
 }
 
-// KillProcessesByPort 结束指定端口的所有进程
-func (a *App) KillProcessesByPort(port uint16) (int, error) {
-	return monitor.KillProcessesByPort(port)
+// ReleasePort 释放占住的端口
+func (a *App) ReleasePort(port uint16) error {
+	return monitor.ReleasePort(port)
 }
 
-// GetListeningPorts 获取所有监听端口（默认 1000 以上）
+// GetListeningPorts 获取所有监听端口（minPort 为 0 时使用设置中的起始端口，默认 1000）
 func (a *App) GetListeningPorts(minPort uint16) ([]model.PortInfo, error) {
+	if minPort == 0 {
+		minPort = uint16(settings.Get().ListeningPortMin)
+	}
 	return monitor.GetListeningPorts(minPort)
 }
 
-// CheckUpdate 检查 GitHub Releases 是否有新版本
+// GetSettings 获取应用设置
+func (a *App) GetSettings() model.Settings {
+	return settings.Get()
+}
+
+// GetDataDirInfo 获取数据目录位置及来源（便携模式、环境变量等）
+func (a *App) GetDataDirInfo() model.DataDirInfo {
+	info := model.DataDirInfo{
+		Path:   datadir.Get(),
+		Source: datadir.Source(),
+	}
+	if err := migration.Err(); err != nil {
+		info.MigrationError = err.Error()
+	}
+	return info
+}
+
+// ExportData 选择保存位置后导出全部历史记录和设置（zip），取消时返回空路径
+func (a *App) ExportData() (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           i18n.T("dialog.export_title"),
+		DefaultFilename: "wincleaner-backup-" + time.Now().Format("20060102") + ".zip",
+		Filters:         []runtime.FileFilter{{DisplayName: i18n.T("dialog.backup_filter"), Pattern: "*.zip"}},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if _, err := backup.Export(path, AppVersion); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ImportData 选择备份文件后导入，mode 为 merge（合并去重）或 replace（替换）；取消时返回 nil
+func (a *App) ImportData(mode string) (*model.ImportResult, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   i18n.T("dialog.import_title"),
+		Filters: []runtime.FileFilter{{DisplayName: i18n.T("dialog.backup_filter"), Pattern: "*.zip"}},
+	})
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return backup.Import(path, mode)
+}
+
+// ExportCSV 把一种历史记录（clean / memory / network）导出为 CSV，取消时返回空路径
+func (a *App) ExportCSV(kind string) (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           i18n.T("dialog.csv_title"),
+		DefaultFilename: "wincleaner-" + kind + "-" + time.Now().Format("20060102") + ".csv",
+		Filters:         []runtime.FileFilter{{DisplayName: i18n.T("dialog.csv_filter"), Pattern: "*.csv"}},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if _, err := backup.ExportCSV(kind, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// GetLocales 支持的界面语言
+func (a *App) GetLocales() []string {
+	return i18n.Locales()
+}
+
+// SetLocale 切换界面语言并保存到设置
+func (a *App) SetLocale(locale string) (model.Settings, error) {
+	s := settings.Get()
+	s.Locale = locale
+	return settings.Update(s)
+}
+
+// SetPrivacyMode 开启或关闭隐私模式（不查询公网 IP、不在线查询归属地、不检查更新）
+func (a *App) SetPrivacyMode(on bool) (model.Settings, error) {
+	s := settings.Get()
+	s.PrivacyMode = on
+	return settings.Update(s)
+}
+
+// GetPublicIP 查询公网 IP 及归属地，force 为 true 时忽略缓存立即查询
+func (a *App) GetPublicIP(force bool) model.PublicIPInfo {
+	return monitor.GetPublicIP(force)
+}
+
+// GetPublicIPProviders 获取可选的公网 IP 服务名称
+func (a *App) GetPublicIPProviders() []string {
+	return publicip.Names()
+}
+
+// UpdateSettings 校验并保存应用设置，返回生效后的设置
+func (a *App) UpdateSettings(s model.Settings) (model.Settings, error) {
+	return settings.Update(s)
+}
+
+// CheckUpdate 检查 GitHub Releases 是否有新版本（隐私模式下不检查）
 func (a *App) CheckUpdate() (*model.UpdateInfo, error) {
 	info := &model.UpdateInfo{
 		CurrentVersion: AppVersion,
 		HasUpdate:      false,
 	}
+	if settings.Get().PrivacyMode {
+		return info, i18n.Errorf("privacy.outbound_disabled")
+	}
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Get("https://api.github.com/repos/yezihack/WinCleaner/releases/latest")
 	if err != nil {
-		return info, fmt.Errorf("请求 GitHub 失败: %w", err)
+		return info, i18n.Errorf("update.request_failed", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return info, fmt.Errorf("GitHub API 返回 %d", resp.StatusCode)
+		return info, i18n.Errorf("update.bad_status", resp.StatusCode)
 	}
 
 	var release struct {
@@ -219,7 +636,7 @@ func (a *App) CheckUpdate() (*model.UpdateInfo, error) {
 		Body    string `json:"body"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return info, fmt.Errorf("解析响应失败: %w", err)
+		return info, i18n.Errorf("update.parse_failed", err)
 	}
 
 	latest := strings.TrimPrefix(release.TagName, "v")
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/pkg/datadir"
+	"win-cleaner/pkg/timeutil"
+	"win-cleaner/pkg/userdirs"
+	"win-cleaner/pkg/winapi"
+)
+
+// localizedError 替换了 pkg 错误文字后的错误，Unwrap 仍返回原错误
+type localizedError struct {
+	msg string
+	err error
+}
+
+func (e *localizedError) Error() string { return e.msg }
+func (e *localizedError) Unwrap() error { return e.err }
+
+// LocalizeError 把错误链中 pkg/ 返回的错误换成当前语言的文字
+//
+// pkg/ 不依赖 internal/i18n，只返回带字段的错误类型或哨兵错误，显示给用户前在这里翻译。
+func LocalizeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	inner, text := localizePkgError(err)
+	if inner == nil {
+		return err
+	}
+	return &localizedError{msg: strings.Replace(err.Error(), inner.Error(), text, 1), err: err}
+}
+
+// localizePkgError 找到错误链中的 pkg 错误，返回该错误及其本地化文字
+func localizePkgError(err error) (error, string) {
+	var (
+		sameDir  *datadir.SameDirError
+		conflict *datadir.ConflictError
+		move     *datadir.MoveError
+		command  *winapi.CommandError
+		badTime  *timeutil.ParseError
+	)
+	switch {
+	case errors.As(err, &sameDir):
+		return sameDir, i18n.T("datadir.same_dir", sameDir.Dir)
+	case errors.As(err, &conflict):
+		return conflict, i18n.T("datadir.conflict", conflict.Dir, strings.Join(conflict.Names, ", "))
+	case errors.As(err, &move):
+		return move, i18n.T("datadir.move_failed", move.Name, move.Err)
+	case errors.As(err, &command):
+		return command, i18n.T("winapi."+command.Op, command.Detail())
+	case errors.As(err, &badTime):
+		return badTime, i18n.T("timeutil.invalid", badTime.Value)
+	case errors.Is(err, userdirs.ErrNotFound):
+		return userdirs.ErrNotFound, i18n.T("userdirs.not_found")
+	case errors.Is(err, winapi.ErrProcessListChanging):
+		return winapi.ErrProcessListChanging, i18n.T("winapi.process_list_changing")
+	}
+	return nil, ""
+}
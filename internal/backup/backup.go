@@ -0,0 +1,326 @@
+// Package backup 历史记录与设置的导出/导入（zip 归档）以及 CSV 报表导出
+//
+// 归档内容：manifest.json 清单 + 每类数据一个 JSON 文件。清单记录归档格式版本、
+// 导出时的数据格式版本和每个文件的 SHA-256，导入时逐一校验。
+package backup
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"win-cleaner/internal/cleaner"
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/memory"
+	"win-cleaner/internal/migration"
+	"win-cleaner/internal/model"
+	"win-cleaner/internal/monitor"
+	"win-cleaner/internal/settings"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+)
+
+const (
+	archiveFormat  = "wincleaner-backup"
+	archiveVersion = 1
+	manifestName   = "manifest.json"
+
+	// 单个文件解压后的大小上限，防止损坏或恶意的归档耗尽内存
+	maxEntrySize = 512 * 1024 * 1024
+)
+
+// 导入模式
+const (
+	ModeMerge   = "merge"   // 合并，跳过重复记录
+	ModeReplace = "replace" // 用归档内容替换本地数据
+)
+
+// 归档中的文件
+const (
+	entryClean    = "clean_history.json"
+	entryMemOpt   = "mem_opt_history.json"
+	entryNet      = "net_traffic.json"
+	entryNetCfg   = "net_config.json"
+	entrySettings = "settings.json"
+)
+
+// Export 把全部历史记录和设置导出到 path（zip）
+func Export(path, appVersion string) (*model.BackupManifest, error) {
+	clean, err := cleaner.ExportCleanRecords()
+	if err != nil {
+		return nil, i18n.Errorf("backup.read_clean", err)
+	}
+	memOpt, err := memory.ExportMemOptRecords()
+	if err != nil {
+		return nil, i18n.Errorf("backup.read_memopt", err)
+	}
+	net, err := monitor.ExportNetRecords()
+	if err != nil {
+		return nil, i18n.Errorf("backup.read_net", err)
+	}
+	netCfg, err := monitor.ExportNetConfig()
+	if err != nil {
+		return nil, i18n.Errorf("backup.read_netcfg", err)
+	}
+	schema, err := storage.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &model.BackupManifest{
+		Format:        archiveFormat,
+		Version:       archiveVersion,
+		SchemaVersion: schema,
+		AppVersion:    appVersion,
+		CreatedAt:     timeutil.Now(),
+	}
+
+	entries := []struct {
+		name    string
+		v       any
+		records int
+	}{
+		{entryClean, model.CleanHistory{Records: clean}, len(clean)},
+		{entryMemOpt, model.MemOptHistory{Records: memOpt}, len(memOpt)},
+		{entryNet, net, len(net.Minute) + len(net.Hourly) + len(net.Daily)},
+		{entryNetCfg, netCfg, len(netCfg.Include) + len(netCfg.Exclude) + len(netCfg.Budgets)},
+		{entrySettings, settings.Get(), 1},
+	}
+
+	// 先写临时文件，完成后再改名，避免留下不完整的归档
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	zw := zip.NewWriter(f)
+	writeErr := func() error {
+		for _, e := range entries {
+			data, err := json.MarshalIndent(e.v, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := writeEntry(zw, e.name, data); err != nil {
+				return err
+			}
+			sum := sha256.Sum256(data)
+			manifest.Entries = append(manifest.Entries, model.BackupEntry{
+				Name:    e.name,
+				Records: e.records,
+				SHA256:  hex.EncodeToString(sum[:]),
+			})
+		}
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeEntry(zw, manifestName, data); err != nil {
+			return err
+		}
+		return zw.Close()
+	}()
+	if err := f.Close(); writeErr == nil {
+		writeErr = err
+	}
+	if writeErr != nil {
+		os.Remove(tmp)
+		return nil, writeErr
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeEntry(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Import 从归档导入数据，mode 为 ModeMerge 或 ModeReplace
+//
+// 合并模式下设置保持本地不变，网卡设置和流量套餐只补充本地没有的项。
+// 数据格式较旧的归档先按迁移步骤转换。全部文件都解析、并与本地数据合并计算完成后才开始写入。
+func Import(path, mode string) (*model.ImportResult, error) {
+	if mode != ModeMerge && mode != ModeReplace {
+		return nil, i18n.Errorf("backup.unknown_mode", mode)
+	}
+	// 本地数据尚未升级到当前格式时写入会丢掉旧字段
+	if err := migration.Err(); err != nil {
+		return nil, i18n.Errorf("history.migration_pending", err)
+	}
+	replace := mode == ModeReplace
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, i18n.Errorf("backup.open_failed", err)
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var manifest model.BackupManifest
+	mf, ok := files[manifestName]
+	if !ok {
+		return nil, i18n.Errorf("backup.missing_manifest", manifestName)
+	}
+	data, err := readEntry(mf)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, i18n.Errorf("backup.manifest_corrupt", err)
+	}
+	if err := checkManifest(&manifest); err != nil {
+		return nil, err
+	}
+
+	// 先校验并解析全部文件，任何一个有问题都不做修改
+	decoded := make(map[string][]byte)
+	for _, e := range manifest.Entries {
+		f, ok := files[e.Name]
+		if !ok {
+			return nil, i18n.Errorf("backup.missing_entry", e.Name)
+		}
+		data, err := readEntry(f)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != e.SHA256 {
+			return nil, i18n.Errorf("backup.checksum", e.Name)
+		}
+		decoded[e.Name] = data
+	}
+	if err := migration.UpgradeArchive(decoded, manifest.SchemaVersion); err != nil {
+		return nil, i18n.Errorf("backup.upgrade_failed", manifest.SchemaVersion, err)
+	}
+
+	var clean model.CleanHistory
+	var memOpt model.MemOptHistory
+	var net model.NetTrafficTiers
+	var netCfg model.NetConfig
+	s := settings.Get()
+	for _, item := range []struct {
+		name string
+		v    any
+	}{
+		{entryClean, &clean},
+		{entryMemOpt, &memOpt},
+		{entryNet, &net},
+		{entryNetCfg, &netCfg},
+		{entrySettings, &s},
+	} {
+		if data, ok := decoded[item.name]; ok {
+			if err := json.Unmarshal(data, item.v); err != nil {
+				return nil, i18n.Errorf("backup.parse_entry", item.name, err)
+			}
+		}
+	}
+	if _, ok := decoded[entrySettings]; ok && replace {
+		if err := settings.Validate(s); err != nil {
+			return nil, i18n.Errorf("backup.invalid_settings", err)
+		}
+	}
+
+	// 先读取本地数据并计算每一项的导入结果，任何一项失败都不做修改
+	type stagedEntry struct {
+		stats  model.ImportEntryStats
+		commit func() error
+	}
+	steps := []struct {
+		name string
+		fn   func() (int, int, func() error, error)
+	}{
+		{entryClean, func() (int, int, func() error, error) { return cleaner.StageCleanImport(clean.Records, replace) }},
+		{entryMemOpt, func() (int, int, func() error, error) { return memory.StageMemOptImport(memOpt.Records, replace) }},
+		{entryNet, func() (int, int, func() error, error) { return monitor.StageNetImport(net, replace) }},
+		{entryNetCfg, func() (int, int, func() error, error) { return monitor.StageNetConfigImport(netCfg, replace) }},
+		{entrySettings, func() (int, int, func() error, error) {
+			if !replace {
+				return 0, 1, func() error { return nil }, nil
+			}
+			return 1, 0, func() error {
+				_, err := settings.Update(s)
+				return err
+			}, nil
+		}},
+	}
+	var staged []stagedEntry
+	for _, step := range steps {
+		if _, ok := decoded[step.name]; !ok {
+			continue
+		}
+		added, skipped, commit, err := step.fn()
+		if err != nil {
+			return nil, i18n.Errorf("backup.import_entry", step.name, err)
+		}
+		staged = append(staged, stagedEntry{
+			stats:  model.ImportEntryStats{Name: step.name, Added: added, Skipped: skipped},
+			commit: commit,
+		})
+	}
+
+	result := &model.ImportResult{Mode: mode}
+	for _, e := range staged {
+		if err := e.commit(); err != nil {
+			return result, i18n.Errorf("backup.commit_entry", e.stats.Name, err)
+		}
+		result.Entries = append(result.Entries, e.stats)
+	}
+	return result, nil
+}
+
+// checkManifest 检查归档格式与版本
+func checkManifest(m *model.BackupManifest) error {
+	if m.Format != archiveFormat {
+		return i18n.Errorf("backup.invalid_archive")
+	}
+	if m.Version > archiveVersion {
+		return i18n.Errorf("backup.newer_format", m.Version, archiveVersion)
+	}
+	schema, err := storage.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	// 较旧的数据格式导入时按迁移步骤转换，较新的无法识别
+	if m.SchemaVersion > schema {
+		return i18n.Errorf("backup.newer_schema", m.SchemaVersion, schema)
+	}
+	return nil
+}
+
+func readEntry(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxEntrySize {
+		return nil, i18n.Errorf("backup.entry_too_large", f.Name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxEntrySize+1))
+	if err != nil {
+		return nil, i18n.Errorf("backup.read_entry", f.Name, err)
+	}
+	if len(data) > maxEntrySize {
+		return nil, i18n.Errorf("backup.entry_too_large", f.Name)
+	}
+	return data, nil
+}
@@ -0,0 +1,126 @@
+package backup
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"win-cleaner/internal/cleaner"
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/memory"
+	"win-cleaner/internal/monitor"
+	"win-cleaner/pkg/timeutil"
+)
+
+// CSV 导出的历史类型
+const (
+	CSVClean   = "clean"
+	CSVMemory  = "memory"
+	CSVNetwork = "network"
+)
+
+// ExportCSV 把一种历史记录导出为 CSV（UTF-8 带 BOM，Excel 可直接打开）
+//
+// 时间列为记录产生时当地的时间，另附时区和月份列，便于按月做透视表。
+func ExportCSV(kind, path string) (int, error) {
+	var header []string
+	var rows [][]string
+
+	switch kind {
+	case CSVClean:
+		records, err := cleaner.ExportCleanRecords()
+		if err != nil {
+			return 0, err
+		}
+		header = csvHeader("time", "zone", "month", "freed_bytes", "freed_mb", "cleaned_count", "categories")
+		for _, r := range records {
+			var cats []string
+			for _, c := range r.Categories {
+				cats = append(cats, fmt.Sprintf("%s:%d", cleaner.CategoryName(c.Category), c.FreedSize))
+			}
+			rows = append(rows, append(timeColumns(r.Timestamp),
+				strconv.FormatInt(r.FreedSize, 10),
+				strconv.FormatFloat(float64(r.FreedSize)/1024/1024, 'f', 2, 64),
+				strconv.Itoa(r.CleanedCount),
+				strings.Join(cats, "; "),
+			))
+		}
+
+	case CSVMemory:
+		records, err := memory.ExportMemOptRecords()
+		if err != nil {
+			return 0, err
+		}
+		header = csvHeader("time", "zone", "month", "freed_mb", "before_percent", "after_percent")
+		for _, r := range records {
+			rows = append(rows, append(timeColumns(r.Timestamp),
+				strconv.FormatFloat(r.FreedMB, 'f', 2, 64),
+				strconv.FormatFloat(r.BeforePercent, 'f', 1, 64),
+				strconv.FormatFloat(r.AfterPercent, 'f', 1, 64),
+			))
+		}
+
+	case CSVNetwork:
+		records, err := monitor.NetDailyTotals()
+		if err != nil {
+			return 0, err
+		}
+		header = csvHeader("date", "zone", "month", "iface", "sent_bytes", "recv_bytes")
+		for _, r := range records {
+			cols := timeColumns(r.Timestamp)
+			cols[0] = strings.SplitN(cols[0], " ", 2)[0]
+			rows = append(rows, append(cols,
+				r.Iface,
+				strconv.FormatUint(r.Sent, 10),
+				strconv.FormatUint(r.Recv, 10),
+			))
+		}
+
+	default:
+		return 0, i18n.Errorf("csv.unknown_kind", kind)
+	}
+
+	return len(rows), writeCSV(path, header, rows)
+}
+
+// csvHeader 按当前语言生成表头
+func csvHeader(keys ...string) []string {
+	header := make([]string, len(keys))
+	for i, k := range keys {
+		header[i] = i18n.T("csv." + k)
+	}
+	return header
+}
+
+// timeColumns 时间、时区、月份三列
+func timeColumns(ts string) []string {
+	t, err := timeutil.Parse(ts)
+	if err != nil {
+		return []string{ts, "", ""}
+	}
+	return []string{t.Format(timeutil.DisplayLayout), t.Format("-07:00"), timeutil.Month(t)}
+}
+
+func writeCSV(path string, header []string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	bw.WriteString("\uFEFF") // BOM，避免 Excel 按本地编码打开导致中文乱码
+	w := csv.NewWriter(bw)
+	w.Write(header)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
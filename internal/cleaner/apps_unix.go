@@ -0,0 +1,40 @@
+//go:build !windows
+
+package cleaner
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// installedApps 已安装软件包名称列表（dpkg / rpm / flatpak，任一可用即可）
+func installedApps() ([]string, error) {
+	queries := [][]string{
+		{"dpkg-query", "-W", "-f=${Package}\n"},
+		{"rpm", "-qa", "--qf", "%{NAME}\n"},
+		{"flatpak", "list", "--app", "--columns=name"},
+	}
+
+	seen := make(map[string]bool)
+	var apps []string
+	var lastErr error
+	for _, q := range queries {
+		output, err := exec.Command(q[0], q[1:]...).Output()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			name := strings.TrimSpace(line)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			apps = append(apps, name)
+		}
+	}
+	if len(apps) == 0 {
+		return nil, lastErr
+	}
+	return apps, nil
+}
@@ -0,0 +1,8 @@
+package cleaner
+
+import "win-cleaner/pkg/winapi"
+
+// installedApps 已安装程序名称列表
+func installedApps() ([]string, error) {
+	return winapi.GetInstalledApps()
+}
@@ -3,14 +3,36 @@ package cleaner
 import (
 	"os"
 	"path/filepath"
+	"win-cleaner/internal/i18n"
+)
+
+// 风险等级
+const (
+	RiskLow    = "low"    // 可随时清理，程序会自动重建
+	RiskMedium = "medium" // 一般安全，但清理后可能需要重新下载或丢失排错信息
+	RiskHigh   = "high"   // 不可恢复或可能影响系统性能，需二次确认
+)
+
+// 分类 ID，持久化和前后端交互都使用 ID，显示名称由 i18n 按当前语言提供
+const (
+	CategoryTemp          = "temp"
+	CategoryWindowsUpdate = "windows_update"
+	CategoryThumbnails    = "thumbnails"
+	CategorySystemLogs    = "system_logs"
+	CategoryBrowserCache  = "browser_cache"
+	CategoryRecycleBin    = "recycle_bin"
+	CategoryPrefetch      = "prefetch"
 )
 
 // 垃圾分类定义
 type JunkCategory struct {
-	Name  string
-	Paths []string // 支持环境变量
-	Glob  string   // 文件匹配模式，空则匹配所有
-	IsRecycleBin bool // 是否为回收站
+	ID           string   // 稳定的分类 ID，见 Category* 常量
+	Name         string   // 当前语言的显示名称
+	Paths        []string // 支持环境变量
+	Glob         string   // 文件匹配模式，空则匹配所有
+	IsRecycleBin bool     // 是否为回收站
+	Risk         string   // 风险等级 RiskLow / RiskMedium / RiskHigh
+	Description  string   // 清理影响说明
 }
 
 // DefaultCategories 默认扫描分类
@@ -19,41 +41,62 @@ func DefaultCategories() []JunkCategory {
 	localAppData := os.Getenv("LOCALAPPDATA")
 	winDir := os.Getenv("WINDIR")
 
-	return []JunkCategory{
+	categories := []JunkCategory{
 		{
-			Name:  "系统临时文件",
+			ID:    CategoryTemp,
 			Paths: []string{temp, filepath.Join(winDir, "Temp")},
+			Risk:  RiskLow,
 		},
 		{
-			Name:  "Windows Update 缓存",
+			ID:    CategoryWindowsUpdate,
 			Paths: []string{filepath.Join(winDir, "SoftwareDistribution", "Download")},
+			Risk:  RiskMedium,
 		},
 		{
-			Name:  "缩略图缓存",
+			ID:    CategoryThumbnails,
 			Paths: []string{filepath.Join(localAppData, "Microsoft", "Windows", "Explorer")},
 			Glob:  "thumbcache_*.db",
+			Risk:  RiskLow,
 		},
 		{
-			Name:  "系统日志",
+			ID:    CategorySystemLogs,
 			Paths: []string{filepath.Join(winDir, "Logs")},
 			Glob:  "*.log",
+			Risk:  RiskMedium,
 		},
 		{
-			Name: "浏览器缓存",
+			ID: CategoryBrowserCache,
 			Paths: []string{
 				filepath.Join(localAppData, "Google", "Chrome", "User Data", "Default", "Cache"),
 				filepath.Join(localAppData, "Microsoft", "Edge", "User Data", "Default", "Cache"),
 			},
+			Risk: RiskLow,
 		},
 		{
-			Name:  "回收站",
-			Paths: []string{},
+			ID:           CategoryRecycleBin,
+			Paths:        []string{},
 			IsRecycleBin: true,
+			Risk:         RiskHigh,
 		},
 		{
-			Name:  "Windows 预读取",
+			ID:    CategoryPrefetch,
 			Paths: []string{filepath.Join(winDir, "Prefetch")},
 			Glob:  "*.pf",
+			Risk:  RiskHigh,
 		},
 	}
+	for i := range categories {
+		categories[i].Name = CategoryName(categories[i].ID)
+		categories[i].Description = i18n.T("category." + categories[i].ID + ".desc")
+	}
+	return categories
+}
+
+// CategoryName 分类 ID 对应的当前语言名称，未知 ID 原样返回
+func CategoryName(id string) string {
+	key := "category." + id
+	if name := i18n.T(key); name != key {
+		return name
+	}
+	return id
 }
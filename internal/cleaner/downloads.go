@@ -0,0 +1,264 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/userdirs"
+)
+
+// 下载文件类型（按扩展名归类）
+var downloadTypes = map[string]string{
+	".exe": "installer", ".msi": "installer", ".msix": "installer", ".appx": "installer",
+	".deb": "installer", ".rpm": "installer", ".appimage": "installer",
+	".zip": "archive", ".7z": "archive", ".rar": "archive", ".tar": "archive",
+	".gz": "archive", ".tgz": "archive", ".xz": "archive", ".bz2": "archive",
+	".iso": "disk_image", ".img": "disk_image", ".vhd": "disk_image", ".vhdx": "disk_image",
+	".pdf": "document", ".doc": "document", ".docx": "document", ".xls": "document",
+	".xlsx": "document", ".ppt": "document", ".pptx": "document", ".txt": "document",
+	".mp4": "media", ".mkv": "media", ".avi": "media", ".mp3": "media",
+	".jpg": "media", ".jpeg": "media", ".png": "media", ".gif": "media",
+}
+
+// 文件名中常见的非产品名片段（安装器后缀、架构、平台等）
+var downloadNoiseWords = map[string]bool{
+	"setup": true, "installer": true, "install": true, "x64": true, "x86": true,
+	"amd64": true, "arm64": true, "win": true, "win32": true, "win64": true,
+	"windows": true, "linux": true, "portable": true, "full": true, "latest": true,
+	"bit": true, "release": true, "stable": true, "offline": true, "online": true,
+	"user": true, "system": true, "lts": true,
+}
+
+// AnalyzeDownloads 分析下载目录：按类型和时间段分组，匹配已安装程序，给出隔离建议
+func AnalyzeDownloads() (*model.DownloadsReport, error) {
+	dir, err := userdirs.Downloads()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.DownloadsReport{Dir: dir}
+
+	// 已安装程序列表获取失败时仅按时间给出建议
+	apps, _ := installedApps()
+	appKeys := make([]appKey, 0, len(apps))
+	for _, name := range apps {
+		if k := normalizeName(name); len(k) >= 3 {
+			appKeys = append(appKeys, appKey{name: name, key: k, words: nameWords(name), tokens: splitTokens(name)})
+		}
+	}
+	report.InstalledApps = len(appKeys)
+
+	now := time.Now()
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // 跳过无权限的文件
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(info.Name()))
+		fileType := downloadTypes[ext]
+		if fileType == "" {
+			fileType = "other"
+		}
+		ageDays := int(now.Sub(info.ModTime()).Hours() / 24)
+
+		file := model.DownloadFileInfo{
+			Path:      path,
+			Name:      info.Name(),
+			Size:      info.Size(),
+			Ext:       ext,
+			FileType:  fileType,
+			ModTime:   info.ModTime().Format("2006-01-02 15:04:05"),
+			AgeDays:   ageDays,
+			AgeBucket: ageBucket(ageDays),
+		}
+
+		if isInstallerType(fileType) {
+			file.MatchedApp = matchInstalledApp(info.Name(), appKeys)
+		}
+		file.Suggest, file.Reason = suggestQuarantine(file)
+
+		report.Files = append(report.Files, file)
+		report.TotalSize += file.Size
+		report.TotalCount++
+		if file.Suggest {
+			report.Suggestions = append(report.Suggestions, file)
+			report.SuggestSize += file.Size
+		}
+		return nil
+	})
+
+	// 按 类型 + 时间段 分组
+	groupMap := make(map[string]*model.DownloadGroup)
+	for _, f := range report.Files {
+		key := f.FileType + "|" + f.AgeBucket
+		if g, ok := groupMap[key]; ok {
+			g.Count++
+			g.Size += f.Size
+		} else {
+			groupMap[key] = &model.DownloadGroup{
+				FileType:  f.FileType,
+				AgeBucket: f.AgeBucket,
+				Count:     1,
+				Size:      f.Size,
+			}
+		}
+	}
+	for _, g := range groupMap {
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		return report.Groups[i].Size > report.Groups[j].Size
+	})
+
+	sort.Slice(report.Suggestions, func(i, j int) bool {
+		return report.Suggestions[i].Size > report.Suggestions[j].Size
+	})
+
+	return report, nil
+}
+
+// ageBucket 按天数划分时间段
+func ageBucket(days int) string {
+	switch {
+	case days <= 7:
+		return "week"
+	case days <= 30:
+		return "month"
+	case days <= 90:
+		return "quarter"
+	default:
+		return "older"
+	}
+}
+
+// isInstallerType 安装包、压缩包和镜像都可能是软件分发包
+func isInstallerType(fileType string) bool {
+	return fileType == "installer" || fileType == "archive" || fileType == "disk_image"
+}
+
+// suggestQuarantine 判断是否建议隔离
+func suggestQuarantine(f model.DownloadFileInfo) (bool, string) {
+	switch {
+	case f.MatchedApp != "" && f.AgeDays > 7:
+		return true, i18n.T("downloads.app_installed", f.MatchedApp)
+	case f.FileType == "installer" && f.AgeBucket == "older":
+		return true, i18n.T("downloads.old_installer", 90)
+	case isInstallerType(f.FileType) && f.AgeBucket == "older":
+		return true, i18n.T("downloads.old_archive", 90)
+	}
+	return false, ""
+}
+
+type appKey struct {
+	name   string
+	key    string   // 去除非字母数字后的小写名称，如 "googlechrome"
+	words  []string // 名称中的单词，如 ["google", "chrome"]
+	tokens []string // 名称中的全部片段，前缀只在片段边界上匹配
+}
+
+// minPrefixMatch 前缀匹配时较短一方的最少字符数，避免 "git" 之类的短名称误配
+const minPrefixMatch = 5
+
+// matchInstalledApp 根据文件名推断产品名，并与已安装程序名称匹配
+//
+// 只接受完全相同、与程序名中的某个单词相同，或在片段边界上的前缀（较短一方至少 minPrefixMatch 个字符），
+// 例如 "Git" 不匹配 "GitKraken-setup.exe"，"Docker Desktop" 匹配 "Docker-Desktop-Installer.exe"。
+func matchInstalledApp(fileName string, apps []appKey) string {
+	tokens := productTokens(fileName)
+	product := strings.Join(tokens, "")
+	if len(product) < 3 {
+		return ""
+	}
+	for _, app := range apps {
+		if app.key == product || slices.Contains(app.words, product) {
+			return app.name
+		}
+		if tokenPrefix(app.tokens, product) || tokenPrefix(tokens, app.key) {
+			return app.name
+		}
+	}
+	return ""
+}
+
+// tokenPrefix s 是否恰好等于 tokens 开头若干片段的拼接，且不短于 minPrefixMatch
+func tokenPrefix(tokens []string, s string) bool {
+	if len(s) < minPrefixMatch {
+		return false
+	}
+	joined := ""
+	for _, t := range tokens {
+		joined += t
+		if len(joined) >= len(s) {
+			return joined == s
+		}
+	}
+	return false
+}
+
+// productTokens 从文件名中提取产品名片段：取版本号、架构等片段之前的部分
+// 例如 "Git-2.43.0-64-bit.exe" → ["git"]，"ChromeSetup.exe" → ["chrome"]
+func productTokens(fileName string) []string {
+	base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	base = strings.TrimSuffix(base, ".tar") // xxx.tar.gz
+
+	var tokens []string
+	for _, token := range splitTokens(base) {
+		if isVersionToken(token) || downloadNoiseWords[token] {
+			break
+		}
+		tokens = append(tokens, token)
+	}
+
+	// 与产品名连写的安装器后缀，如 "ChromeSetup"
+	if n := len(tokens); n > 0 {
+		last := tokens[n-1]
+		for _, suffix := range []string{"setup", "installer", "install"} {
+			last = strings.TrimSuffix(last, suffix)
+		}
+		if last == "" {
+			tokens = tokens[:n-1]
+		} else {
+			tokens[n-1] = last
+		}
+	}
+	return tokens
+}
+
+// splitTokens 按非字母数字字符切分并转小写
+func splitTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// isVersionToken 判断是否为版本号片段，如 "2"、"v1"、"2023a"
+func isVersionToken(token string) bool {
+	t := strings.TrimPrefix(token, "v")
+	return t != "" && unicode.IsDigit(rune(t[0]))
+}
+
+// normalizeName 去掉空格和符号，转小写
+func normalizeName(name string) string {
+	return strings.Join(splitTokens(name), "")
+}
+
+// nameWords 程序名中长度 ≥3 的单词（忽略版本号）
+func nameWords(name string) []string {
+	var words []string
+	for _, t := range splitTokens(name) {
+		if len(t) >= 3 && !isVersionToken(t) {
+			words = append(words, t)
+		}
+	}
+	return words
+}
@@ -0,0 +1,38 @@
+package cleaner
+
+import "testing"
+
+func testAppKeys(names ...string) []appKey {
+	keys := make([]appKey, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, appKey{name: name, key: normalizeName(name), words: nameWords(name), tokens: splitTokens(name)})
+	}
+	return keys
+}
+
+func TestMatchInstalledApp(t *testing.T) {
+	apps := testAppKeys("Git", "Google Chrome", "Docker Desktop", "Mozilla Firefox", "zip", "curl", "Visual Studio Code", "7-Zip 23.01 (x64)")
+	cases := []struct {
+		file, want string
+	}{
+		{"Git-2.43.0-64-bit.exe", "Git"},
+		{"GitKraken-setup.exe", ""},
+		{"ChromeSetup.exe", "Google Chrome"},
+		{"googlechrome_installer.msi", "Google Chrome"},
+		{"Docker Desktop Installer.exe", "Docker Desktop"},
+		{"Docker-Desktop-4.26.1.exe", "Docker Desktop"},
+		{"Firefox Setup 121.0.exe", "Mozilla Firefox"},
+		{"zip-3.0.tar.gz", "zip"},
+		{"zipper-1.2.zip", ""},
+		{"curlie_1.7.2_linux_amd64.deb", ""},
+		{"GitHubDesktopSetup-x64.exe", ""},
+		{"Visual-Studio-Code-1.85.exe", "Visual Studio Code"},
+		{"7z2301-x64.exe", ""},
+		{"report.pdf", ""},
+	}
+	for _, tc := range cases {
+		if got := matchInstalledApp(tc.file, apps); got != tc.want {
+			t.Errorf("matchInstalledApp(%q) = %q, want %q", tc.file, got, tc.want)
+		}
+	}
+}
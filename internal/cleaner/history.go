@@ -1,67 +1,55 @@
 package cleaner
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"time"
 
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/migration"
 	"win-cleaner/internal/model"
-	"win-cleaner/pkg/datadir"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
 )
 
-// getHistoryPath 获取历史文件路径 (~/.wincleaner/clean_history.json)
-func getHistoryPath() string {
-	return datadir.FilePath("clean_history.json")
-}
+const historyFile = "clean_history.json"
 
 // loadHistory 加载历史记录
 func loadHistory() (*model.CleanHistory, error) {
-	path := getHistoryPath()
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &model.CleanHistory{}, nil
-		}
-		return nil, err
-	}
-
 	var history model.CleanHistory
-	if err := json.Unmarshal(data, &history); err != nil {
-		return &model.CleanHistory{}, nil
+	if err := storage.Load(historyFile, &history); err != nil {
+		return nil, err
 	}
 	return &history, nil
 }
 
-// saveHistory 保存历史记录
-func saveHistory(history *model.CleanHistory) error {
-	path := getHistoryPath()
-
-	data, err := json.MarshalIndent(history, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0644)
-}
-
 // RecordClean 记录一次清理
 func RecordClean(result model.CleanResult) error {
-	history, err := loadHistory()
-	if err != nil {
-		return err
+	if err := migration.Err(); err != nil {
+		return i18n.Errorf("history.migration_pending", err)
 	}
-
-	now := time.Now()
 	record := model.CleanRecord{
-		Date:         now.Format("2006-01-02"),
-		Time:         now.Format("15:04:05"),
+		Timestamp:    timeutil.Now(),
 		FreedSize:    result.FreedSize,
 		CleanedCount: result.CleanedCount,
+		Categories:   persistedCategories(result.Categories),
 	}
 
-	history.Records = append(history.Records, record)
-	return saveHistory(history)
+	var history model.CleanHistory
+	return storage.Update(historyFile, &history, func() error {
+		history.Records = append(history.Records, record)
+		return nil
+	})
+}
+
+// persistedCategories 去掉分类显示名称，历史记录只保存分类 ID
+func persistedCategories(cats []model.CategoryFreed) []model.CategoryFreed {
+	out := make([]model.CategoryFreed, len(cats))
+	for i, c := range cats {
+		c.CategoryName = ""
+		out[i] = c
+	}
+	return out
 }
 
 // GetCleanHistoryStats 获取清理历史统计
@@ -77,7 +65,7 @@ func GetCleanHistoryStats() (*model.CleanHistoryStats, error) {
 
 	if len(history.Records) == 0 {
 		stats.LastCleanTime = ""
-		stats.LastCleanAgo = "从未清理"
+		stats.LastCleanAgo = i18n.T("history.never_cleaned")
 		return stats, nil
 	}
 
@@ -89,21 +77,38 @@ func GetCleanHistoryStats() (*model.CleanHistoryStats, error) {
 
 	// 上次清理
 	last := history.Records[len(history.Records)-1]
-	stats.LastCleanTime = last.Date + " " + last.Time
-	lastTime, err := time.Parse("2006-01-02 15:04:05", stats.LastCleanTime)
-	if err == nil {
-		stats.LastCleanAgo = formatDuration(time.Since(lastTime))
+	stats.LastCleanTime = timeutil.Display(last.Timestamp)
+	if lastTime, err := timeutil.Parse(last.Timestamp); err == nil {
+		stats.LastCleanAgo = i18n.RelativeTime(time.Since(lastTime))
 	}
 
-	// 按天汇总（近30天）
+	// 按天、按月汇总，日期取清理时所在时区
 	dailyMap := make(map[string]*model.DailyStat)
+	monthlyMap := make(map[string]*model.MonthlyStat)
 	for _, r := range history.Records {
-		if d, ok := dailyMap[r.Date]; ok {
+		at, err := timeutil.Parse(r.Timestamp)
+		if err != nil {
+			continue
+		}
+		day := timeutil.Day(at)
+		if d, ok := dailyMap[day]; ok {
 			d.FreedSize += r.FreedSize
 			d.Count += r.CleanedCount
 		} else {
-			dailyMap[r.Date] = &model.DailyStat{
-				Date:      r.Date,
+			dailyMap[day] = &model.DailyStat{
+				Date:      day,
+				FreedSize: r.FreedSize,
+				Count:     r.CleanedCount,
+			}
+		}
+
+		month := timeutil.Month(at)
+		if m, ok := monthlyMap[month]; ok {
+			m.FreedSize += r.FreedSize
+			m.Count += r.CleanedCount
+		} else {
+			monthlyMap[month] = &model.MonthlyStat{
+				Month:     month,
 				FreedSize: r.FreedSize,
 				Count:     r.CleanedCount,
 			}
@@ -111,7 +116,7 @@ func GetCleanHistoryStats() (*model.CleanHistoryStats, error) {
 	}
 
 	// 取近30天
-	cutoff := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+	cutoff := timeutil.Day(time.Now().AddDate(0, 0, -30))
 	for _, d := range dailyMap {
 		if d.Date >= cutoff {
 			stats.DailyStats = append(stats.DailyStats, *d)
@@ -122,20 +127,6 @@ func GetCleanHistoryStats() (*model.CleanHistoryStats, error) {
 	})
 
 	// 按月汇总
-	monthlyMap := make(map[string]*model.MonthlyStat)
-	for _, r := range history.Records {
-		month := r.Date[:7] // YYYY-MM
-		if m, ok := monthlyMap[month]; ok {
-			m.FreedSize += r.FreedSize
-			m.Count += r.CleanedCount
-		} else {
-			monthlyMap[month] = &model.MonthlyStat{
-				Month:     month,
-				FreedSize: r.FreedSize,
-				Count:     r.CleanedCount,
-			}
-		}
-	}
 	for _, m := range monthlyMap {
 		stats.MonthlyStats = append(stats.MonthlyStats, *m)
 	}
@@ -143,23 +134,138 @@ func GetCleanHistoryStats() (*model.CleanHistoryStats, error) {
 		return stats.MonthlyStats[i].Month < stats.MonthlyStats[j].Month
 	})
 
+	stats.CategoryTrends = categoryTrends(history.Records)
+
 	return stats, nil
 }
 
-// formatDuration 格式化时间间隔为中文
-func formatDuration(d time.Duration) string {
-	days := int(d.Hours() / 24)
-	hours := int(d.Hours()) % 24
-	minutes := int(d.Minutes()) % 60
+// regrowthTarget 建议清理间隔的参考值：分类回涨到该大小时值得再清理一次
+const regrowthTarget = 256 * 1024 * 1024
 
-	if days > 0 {
-		return fmt.Sprintf("%d天%d小时前", days, hours)
+// categoryTrends 按分类汇总清理记录，并估算每个分类的回涨速度
+//
+// 两次清理之间的释放量即为该分类在这段时间内重新积累的大小，
+// 回涨速度 = 后续各次清理释放量之和 / 对应时间间隔之和。
+func categoryTrends(records []model.CleanRecord) []model.CategoryTrend {
+	trendMap := make(map[string]*model.CategoryTrend)
+	lastTime := make(map[string]time.Time)
+	regrowBytes := make(map[string]int64)
+	regrowDur := make(map[string]time.Duration)
+
+	for _, r := range records {
+		at, err := timeutil.Parse(r.Timestamp)
+		if err != nil {
+			continue
+		}
+		for _, c := range r.Categories {
+			t, ok := trendMap[c.Category]
+			if !ok {
+				t = &model.CategoryTrend{Category: c.Category, CategoryName: CategoryName(c.Category)}
+				trendMap[c.Category] = t
+			}
+			t.TotalFreed += c.FreedSize
+			t.TotalCount += c.CleanedCount
+			t.CleanTimes++
+			t.LastCleanTime = r.Timestamp
+			t.Points = append(t.Points, model.CategoryPoint{
+				Time:      t.LastCleanTime,
+				FreedSize: c.FreedSize,
+				Count:     c.CleanedCount,
+			})
+
+			if prev, ok := lastTime[c.Category]; ok && at.After(prev) {
+				regrowBytes[c.Category] += c.FreedSize
+				regrowDur[c.Category] += at.Sub(prev)
+			}
+			lastTime[c.Category] = at
+		}
+	}
+
+	var trends []model.CategoryTrend
+	for name, t := range trendMap {
+		t.AvgFreed = t.TotalFreed / int64(t.CleanTimes)
+		if days := regrowDur[name].Hours() / 24; days > 0 {
+			t.RegrowthPerDay = int64(float64(regrowBytes[name]) / days)
+			t.RegrowthWeekly = t.RegrowthPerDay * 7
+			t.SuggestDays = suggestInterval(t.RegrowthPerDay)
+		}
+		trends = append(trends, *t)
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		return trends[i].TotalFreed > trends[j].TotalFreed
+	})
+	return trends
+}
+
+// suggestInterval 根据回涨速度计算建议清理间隔（1~90 天）
+func suggestInterval(perDay int64) int {
+	if perDay <= 0 {
+		return 90
 	}
-	if hours > 0 {
-		return fmt.Sprintf("%d小时%d分钟前", hours, minutes)
+	days := int(regrowthTarget / perDay)
+	if days < 1 {
+		return 1
 	}
-	if minutes > 0 {
-		return fmt.Sprintf("%d分钟前", minutes)
+	if days > 90 {
+		return 90
 	}
-	return "刚刚"
+	return days
+}
+
+// ExportCleanRecords 导出全部清理记录
+func ExportCleanRecords() ([]model.CleanRecord, error) {
+	history, err := loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	return history.Records, nil
+}
+
+// StageCleanImport 读取本地记录并计算导入结果，不修改文件；返回的 commit 执行写入
+//
+// replace 为 true 时替换本地全部记录；否则合并，时间、释放大小和文件数都相同的记录视为重复。
+// commit 按写入时的文件重新合并，其间新增的本地记录不会丢失。
+func StageCleanImport(records []model.CleanRecord, replace bool) (added, skipped int, commit func() error, err error) {
+	history, err := loadHistory()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	added, skipped = mergeCleanRecords(history, records, replace)
+	commit = func() error {
+		var history model.CleanHistory
+		return storage.Update(historyFile, &history, func() error {
+			mergeCleanRecords(&history, records, replace)
+			return nil
+		})
+	}
+	return added, skipped, commit, nil
+}
+
+// mergeCleanRecords 把导入的记录并入 history 并按时间排序
+func mergeCleanRecords(history *model.CleanHistory, records []model.CleanRecord, replace bool) (added, skipped int) {
+	if replace {
+		history.Records = nil
+	}
+	seen := make(map[string]bool, len(history.Records))
+	for _, r := range history.Records {
+		seen[cleanRecordKey(r)] = true
+	}
+	for _, r := range records {
+		key := cleanRecordKey(r)
+		if seen[key] {
+			skipped++
+			continue
+		}
+		seen[key] = true
+		history.Records = append(history.Records, r)
+		added++
+	}
+	sort.SliceStable(history.Records, func(i, j int) bool {
+		return timeutil.Before(history.Records[i].Timestamp, history.Records[j].Timestamp)
+	})
+	return added, skipped
+}
+
+func cleanRecordKey(r model.CleanRecord) string {
+	return fmt.Sprintf("%s|%d|%d", r.Timestamp, r.FreedSize, r.CleanedCount)
 }
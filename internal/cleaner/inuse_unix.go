@@ -0,0 +1,13 @@
+//go:build !windows
+
+package cleaner
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isInUseError 判断删除失败是否因为文件被其他进程占用
+func isInUseError(err error) bool {
+	return errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.ETXTBSY)
+}
@@ -0,0 +1,12 @@
+package cleaner
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isInUseError 判断删除失败是否因为文件被其他进程占用
+func isInUseError(err error) bool {
+	return errors.Is(err, windows.ERROR_SHARING_VIOLATION) || errors.Is(err, windows.ERROR_LOCK_VIOLATION)
+}
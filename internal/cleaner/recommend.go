@@ -0,0 +1,102 @@
+package cleaner
+
+import (
+	"os"
+	"time"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/timeutil"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+const (
+	lowDiskPercent    = 10.0                    // 剩余空间低于该百分比视为不足
+	lowDiskBytes      = 10 * 1024 * 1024 * 1024 // 剩余空间低于 10GB 视为不足
+	mediumRiskMinSize = 1024 * 1024 * 1024      // 中风险分类超过 1GB 才推荐
+	minRecommendSize  = 1024 * 1024             // 小于 1MB 的分类不值得清理
+)
+
+// Recommend 根据当前扫描结果、清理历史和系统盘剩余空间给出推荐清理的分类
+//
+// 规则：
+//   - 高风险分类从不自动选中；
+//   - 最近刚清理过、尚未回涨到建议间隔的分类不推荐；
+//   - 低风险分类只要有内容即推荐；
+//   - 中风险分类在系统盘空间不足或占用较大时推荐。
+func Recommend(results []model.ScanResult) (*model.CleanRecommendation, error) {
+	rec := &model.CleanRecommendation{}
+
+	if usage, err := disk.Usage(systemDrive()); err == nil {
+		rec.DiskFree = usage.Free
+		rec.DiskTotal = usage.Total
+		rec.LowDisk = usage.Free < lowDiskBytes || 100-usage.UsedPercent < lowDiskPercent
+	}
+
+	trends := make(map[string]model.CategoryTrend)
+	if stats, err := GetCleanHistoryStats(); err == nil {
+		for _, t := range stats.CategoryTrends {
+			trends[t.Category] = t
+		}
+	}
+
+	for _, r := range results {
+		item := model.RecommendItem{
+			Category:     r.Category,
+			CategoryName: CategoryName(r.Category),
+			Risk:         r.Risk,
+			Size:         r.Size,
+		}
+		item.Recommended, item.Reason = recommendCategory(r, trends[r.Category], rec.LowDisk)
+		if item.Recommended {
+			rec.Recommended = append(rec.Recommended, r.Category)
+			rec.RecommendedSize += r.Size
+		}
+		rec.Items = append(rec.Items, item)
+	}
+
+	return rec, nil
+}
+
+// recommendCategory 判断单个分类是否推荐清理，并给出原因
+func recommendCategory(r model.ScanResult, trend model.CategoryTrend, lowDisk bool) (bool, string) {
+	if r.Size < minRecommendSize {
+		return false, i18n.T("recommend.too_small")
+	}
+	if r.Risk == RiskHigh {
+		return false, i18n.T("recommend.high_risk")
+	}
+
+	if trend.LastCleanTime != "" && trend.SuggestDays > 0 && !lowDisk {
+		last, err := timeutil.Parse(trend.LastCleanTime)
+		if err == nil {
+			days := int(time.Since(last).Hours() / 24)
+			if days < trend.SuggestDays && r.Size < regrowthTarget {
+				return false, i18n.T("recommend.recently_cleaned", days, trend.SuggestDays)
+			}
+		}
+	}
+
+	switch r.Risk {
+	case RiskLow:
+		return true, i18n.T("recommend.low_risk")
+	case RiskMedium:
+		if lowDisk {
+			return true, i18n.T("recommend.low_disk")
+		}
+		if r.Size >= mediumRiskMinSize {
+			return true, i18n.T("recommend.large")
+		}
+		return false, i18n.T("recommend.medium_small")
+	}
+	return false, i18n.T("recommend.unknown_risk")
+}
+
+// systemDrive 系统盘根目录
+func systemDrive() string {
+	if drive := os.Getenv("SystemDrive"); drive != "" {
+		return drive + `\`
+	}
+	return `C:\`
+}
@@ -13,7 +13,10 @@ func Scan(categories []JunkCategory) []model.ScanResult {
 
 	for _, cat := range categories {
 		result := model.ScanResult{
-			Category: cat.Name,
+			Category:     cat.ID,
+			CategoryName: cat.Name,
+			Risk:         cat.Risk,
+			Description:  cat.Description,
 		}
 
 		if cat.IsRecycleBin {
@@ -27,7 +30,7 @@ func Scan(categories []JunkCategory) []model.ScanResult {
 				if dir == "" {
 					continue
 				}
-				items := scanDir(dir, cat.Glob, cat.Name)
+				items := scanDir(dir, cat.Glob, cat.ID)
 				result.Items = append(result.Items, items...)
 			}
 			for _, item := range result.Items {
@@ -81,13 +84,34 @@ func scanDir(dir, glob, category string) []model.JunkItem {
 func Clean(items []model.JunkItem) model.CleanResult {
 	var result model.CleanResult
 
+	// 按分类汇总，保持首次出现的顺序
+	catIndex := make(map[string]int)
 	for _, item := range items {
 		if err := os.Remove(item.Path); err != nil {
 			result.FailedCount++
+			result.Failures = append(result.Failures, model.CleanFailure{
+				Path:     item.Path,
+				Size:     item.Size,
+				Category: item.Category,
+				Error:    err.Error(),
+				InUse:    isInUseError(err),
+			})
 			continue
 		}
 		result.FreedSize += item.Size
 		result.CleanedCount++
+
+		idx, ok := catIndex[item.Category]
+		if !ok {
+			idx = len(result.Categories)
+			catIndex[item.Category] = idx
+			result.Categories = append(result.Categories, model.CategoryFreed{
+				Category:     item.Category,
+				CategoryName: CategoryName(item.Category),
+			})
+		}
+		result.Categories[idx].FreedSize += item.Size
+		result.Categories[idx].CleanedCount++
 	}
 
 	return result
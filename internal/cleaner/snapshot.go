@@ -0,0 +1,333 @@
+package cleaner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+)
+
+const (
+	snapshotFile        = "scan_snapshots.json"
+	maxSnapshotsPerKind = 30 // 每种扫描保留的摘要数量
+	topDirsPerCategory  = 10 // 每个分类记录的目录数量
+)
+
+func loadSnapshots() (*model.ScanSnapshotHistory, error) {
+	var h model.ScanSnapshotHistory
+	if err := storage.Load(snapshotFile, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// SaveScanSnapshot 保存一次垃圾扫描的摘要（分类大小、数量、占用最多的目录）
+func SaveScanSnapshot(categories []JunkCategory, results []model.ScanResult) (*model.ScanSnapshot, error) {
+	roots := make(map[string][]string)
+	for _, cat := range categories {
+		roots[cat.ID] = cat.Paths
+	}
+
+	snap := model.ScanSnapshot{Kind: "junk"}
+	for _, r := range results {
+		top, all := topDirs(r, roots[r.Category])
+		cs := model.CategorySnapshot{
+			Category: r.Category,
+			Size:     r.Size,
+			Count:    r.Count,
+			TopDirs:  top,
+			Dirs:     all,
+		}
+		snap.Categories = append(snap.Categories, cs)
+		snap.TotalSize += r.Size
+		snap.TotalCount += r.Count
+	}
+	return addSnapshot(snap)
+}
+
+// SaveLargeFileSnapshot 保存一次大文件扫描的结果
+func SaveLargeFileSnapshot(root string, minSizeMB int64, files []model.LargeFileInfo) (*model.ScanSnapshot, error) {
+	snap := model.ScanSnapshot{
+		Kind:      "large",
+		Root:      root,
+		MinSizeMB: minSizeMB,
+		Files:     files,
+	}
+	for _, f := range files {
+		snap.TotalSize += f.Size
+	}
+	snap.TotalCount = len(files)
+	return addSnapshot(snap)
+}
+
+// addSnapshot 追加摘要并按类型裁剪旧记录
+func addSnapshot(snap model.ScanSnapshot) (*model.ScanSnapshot, error) {
+	now := time.Now()
+	snap.Time = timeutil.Format(now)
+	snap.ID = fmt.Sprintf("%s-%d", snap.Kind, now.UnixNano())
+
+	var h model.ScanSnapshotHistory
+	err := storage.Update(snapshotFile, &h, func() error {
+		h.Snapshots = append(h.Snapshots, snap)
+
+		// 每种类型只保留最近的 maxSnapshotsPerKind 条
+		kept := make(map[string]int)
+		var filtered []model.ScanSnapshot
+		for i := len(h.Snapshots) - 1; i >= 0; i-- {
+			s := h.Snapshots[i]
+			if kept[s.Kind] >= maxSnapshotsPerKind {
+				continue
+			}
+			kept[s.Kind]++
+			filtered = append(filtered, s)
+		}
+		for i, j := 0, len(filtered)-1; i < j; i, j = i+1, j-1 {
+			filtered[i], filtered[j] = filtered[j], filtered[i]
+		}
+		h.Snapshots = filtered
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// ListScanSnapshots 列出扫描摘要（kind 为空返回全部），按时间倒序，不含明细
+func ListScanSnapshots(kind string) ([]model.ScanSnapshot, error) {
+	h, err := loadSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []model.ScanSnapshot
+	for i := len(h.Snapshots) - 1; i >= 0; i-- {
+		s := h.Snapshots[i]
+		if kind != "" && s.Kind != kind {
+			continue
+		}
+		s.Categories = nil
+		s.Files = nil
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// CompareScanSnapshots 对比两次扫描
+//
+// newID 为空时取最新一次；oldID 为空时取 newID 之前最近一次同类型（大文件扫描还需同一路径）的扫描。
+func CompareScanSnapshots(oldID, newID string) (*model.ScanDiff, error) {
+	h, err := loadSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	if len(h.Snapshots) == 0 {
+		return nil, i18n.Errorf("snapshot.none")
+	}
+
+	newIdx := len(h.Snapshots) - 1
+	if newID != "" {
+		newIdx = findSnapshot(h.Snapshots, newID)
+		if newIdx < 0 {
+			return nil, i18n.Errorf("snapshot.not_found", newID)
+		}
+	}
+	newSnap := h.Snapshots[newIdx]
+
+	oldIdx := -1
+	if oldID != "" {
+		oldIdx = findSnapshot(h.Snapshots, oldID)
+	} else {
+		for i := newIdx - 1; i >= 0; i-- {
+			s := h.Snapshots[i]
+			if s.Kind == newSnap.Kind && s.Root == newSnap.Root {
+				oldIdx = i
+				break
+			}
+		}
+	}
+	if oldIdx < 0 {
+		return nil, i18n.Errorf("snapshot.no_previous")
+	}
+	oldSnap := h.Snapshots[oldIdx]
+	if oldSnap.Kind != newSnap.Kind {
+		return nil, i18n.Errorf("snapshot.kind_mismatch")
+	}
+
+	diff := &model.ScanDiff{
+		OldID:     oldSnap.ID,
+		OldTime:   oldSnap.Time,
+		NewID:     newSnap.ID,
+		NewTime:   newSnap.Time,
+		Kind:      newSnap.Kind,
+		SizeDelta: newSnap.TotalSize - oldSnap.TotalSize,
+	}
+	diffCategories(diff, oldSnap, newSnap)
+	diffFiles(diff, oldSnap, newSnap)
+	return diff, nil
+}
+
+func findSnapshot(snaps []model.ScanSnapshot, id string) int {
+	for i, s := range snaps {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// diffCategories 分类大小变化 + 新增/消失的目录
+//
+// 摘要只保存每个分类前 topDirsPerCategory 个目录的大小，新增/消失按全部目录判断，
+// 排名变化（进入或跌出前 N 名）不算新增或消失。
+func diffCategories(diff *model.ScanDiff, oldSnap, newSnap model.ScanSnapshot) {
+	oldCats := make(map[string]model.CategorySnapshot)
+	oldDirs := allDirs(oldSnap)
+	for _, c := range oldSnap.Categories {
+		oldCats[c.Category] = c
+	}
+
+	newDirs := allDirs(newSnap)
+	for _, c := range newSnap.Categories {
+		o := oldCats[c.Category]
+		diff.CategoryDeltas = append(diff.CategoryDeltas, model.CategoryDelta{
+			Category:     c.Category,
+			CategoryName: CategoryName(c.Category),
+			OldSize:      o.Size,
+			NewSize:      c.Size,
+			SizeDelta:    c.Size - o.Size,
+			OldCount:     o.Count,
+			NewCount:     c.Count,
+			CountDelta:   c.Count - o.Count,
+		})
+		delete(oldCats, c.Category)
+
+		for _, d := range c.TopDirs {
+			if !oldDirs[c.Category+"|"+d.Path] {
+				d.Category = c.Category
+				diff.AddedDirs = append(diff.AddedDirs, d)
+			}
+		}
+	}
+	// 新扫描中已不存在的分类
+	for _, o := range oldCats {
+		diff.CategoryDeltas = append(diff.CategoryDeltas, model.CategoryDelta{
+			Category:     o.Category,
+			CategoryName: CategoryName(o.Category),
+			OldSize:      o.Size,
+			SizeDelta:    -o.Size,
+			OldCount:     o.Count,
+			CountDelta:   -o.Count,
+		})
+	}
+
+	for _, c := range oldSnap.Categories {
+		for _, d := range c.TopDirs {
+			if !newDirs[c.Category+"|"+d.Path] {
+				d.Category = c.Category
+				diff.RemovedDirs = append(diff.RemovedDirs, d)
+			}
+		}
+	}
+
+	sort.Slice(diff.CategoryDeltas, func(i, j int) bool {
+		return diff.CategoryDeltas[i].SizeDelta > diff.CategoryDeltas[j].SizeDelta
+	})
+	sort.Slice(diff.AddedDirs, func(i, j int) bool {
+		return diff.AddedDirs[i].Size > diff.AddedDirs[j].Size
+	})
+	sort.Slice(diff.RemovedDirs, func(i, j int) bool {
+		return diff.RemovedDirs[i].Size > diff.RemovedDirs[j].Size
+	})
+}
+
+// allDirs 摘要中各分类的全部一级子目录（键为 "分类|路径"）；旧摘要没有 Dirs 时只能用前 N 名
+func allDirs(snap model.ScanSnapshot) map[string]bool {
+	dirs := make(map[string]bool)
+	for _, c := range snap.Categories {
+		if c.Dirs == nil {
+			for _, d := range c.TopDirs {
+				dirs[c.Category+"|"+d.Path] = true
+			}
+			continue
+		}
+		for _, p := range c.Dirs {
+			dirs[c.Category+"|"+p] = true
+		}
+	}
+	return dirs
+}
+
+// diffFiles 大文件新增/消失
+func diffFiles(diff *model.ScanDiff, oldSnap, newSnap model.ScanSnapshot) {
+	oldFiles := make(map[string]bool)
+	for _, f := range oldSnap.Files {
+		oldFiles[strings.ToLower(f.Path)] = true
+	}
+	newFiles := make(map[string]bool)
+	for _, f := range newSnap.Files {
+		newFiles[strings.ToLower(f.Path)] = true
+		if !oldFiles[strings.ToLower(f.Path)] {
+			diff.AddedFiles = append(diff.AddedFiles, f)
+		}
+	}
+	for _, f := range oldSnap.Files {
+		if !newFiles[strings.ToLower(f.Path)] {
+			diff.RemovedFiles = append(diff.RemovedFiles, f)
+		}
+	}
+}
+
+// topDirs 按分类根目录下的一级子目录汇总占用，返回前 topDirsPerCategory 个及全部目录路径
+func topDirs(result model.ScanResult, roots []string) ([]model.DirSize, []string) {
+	dirMap := make(map[string]*model.DirSize)
+	for _, item := range result.Items {
+		dir := topLevelDir(item.Path, roots)
+		if d, ok := dirMap[dir]; ok {
+			d.Size += item.Size
+			d.Count++
+		} else {
+			dirMap[dir] = &model.DirSize{Path: dir, Size: item.Size, Count: 1}
+		}
+	}
+
+	dirs := make([]model.DirSize, 0, len(dirMap))
+	paths := make([]string, 0, len(dirMap))
+	for _, d := range dirMap {
+		dirs = append(dirs, *d)
+		paths = append(paths, d.Path)
+	}
+	sort.Strings(paths)
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].Size > dirs[j].Size
+	})
+	if len(dirs) > topDirsPerCategory {
+		dirs = dirs[:topDirsPerCategory]
+	}
+	return dirs, paths
+}
+
+// topLevelDir 返回文件所在的根目录下一级子目录；文件直接位于根目录时返回根目录
+func topLevelDir(path string, roots []string) string {
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		parts := strings.SplitN(rel, string(filepath.Separator), 2)
+		if len(parts) < 2 {
+			return root
+		}
+		return filepath.Join(root, parts[0])
+	}
+	return filepath.Dir(path)
+}
@@ -0,0 +1,107 @@
+// Package i18n 界面文字与错误信息的多语言支持
+//
+// 文字按 key 保存在各语言的消息表中（messages_*.go），key 按模块分组，如 "recommend.low_risk"。
+// 当前语言缺少某条消息时回退到默认语言，仍找不到时返回 key 本身。
+package i18n
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 支持的语言
+const (
+	ZhCN = "zh-CN"
+	EnUS = "en-US"
+
+	DefaultLocale = ZhCN
+)
+
+var catalogs = map[string]map[string]string{
+	ZhCN: zhCN,
+	EnUS: enUS,
+}
+
+var (
+	mu      sync.RWMutex
+	current = DefaultLocale
+)
+
+// Locales 支持的语言列表
+func Locales() []string {
+	return []string{ZhCN, EnUS}
+}
+
+// Supported 是否支持该语言
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// SetLocale 切换当前语言
+func SetLocale(locale string) error {
+	if !Supported(locale) {
+		return Errorf("error.unsupported_locale", locale)
+	}
+	mu.Lock()
+	current = locale
+	mu.Unlock()
+	return nil
+}
+
+// Locale 当前语言
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T 返回当前语言的消息，有参数时按 fmt.Sprintf 格式化
+func T(key string, args ...any) string {
+	msg := lookup(key)
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Errorf 以当前语言的消息创建错误，消息中可使用 %w 包装原始错误
+func Errorf(key string, args ...any) error {
+	return fmt.Errorf(lookup(key), args...)
+}
+
+func lookup(key string) string {
+	if msg, ok := catalogs[Locale()][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[DefaultLocale][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// RelativeTime 把时间间隔格式化为“多久以前”，如 "3天2小时前" / "3 days 2 hours ago"
+func RelativeTime(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return T("time.days_hours_ago", days, unit("day", days), hours, unit("hour", hours))
+	case hours > 0:
+		return T("time.hours_minutes_ago", hours, unit("hour", hours), minutes, unit("minute", minutes))
+	case minutes > 0:
+		return T("time.minutes_ago", minutes, unit("minute", minutes))
+	}
+	return T("time.just_now")
+}
+
+// unit 时间单位（英文区分单复数，中文消息中不使用）
+func unit(name string, n int) string {
+	if n == 1 {
+		return T("time.unit." + name)
+	}
+	return T("time.unit." + name + "s")
+}
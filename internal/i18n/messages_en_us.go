@@ -0,0 +1,189 @@
+package i18n
+
+var enUS = map[string]string{
+	// Junk categories
+	"category.temp":                "Temporary files",
+	"category.temp.desc":           "Files left behind by running programs. Files still in use are skipped automatically.",
+	"category.windows_update":      "Windows Update cache",
+	"category.windows_update.desc": "Downloaded update packages. Safe to remove once updates are installed; pending updates will be downloaded again.",
+	"category.thumbnails":          "Thumbnail cache",
+	"category.thumbnails.desc":     "Image previews used by File Explorer. They are rebuilt the next time a folder is opened.",
+	"category.system_logs":         "System logs",
+	"category.system_logs.desc":    "Logs written by system components. Not needed day to day, but history for troubleshooting will be lost.",
+	"category.browser_cache":       "Browser cache",
+	"category.browser_cache.desc":  "Cached images and scripts. Logins and bookmarks are kept; pages load a little slower the first time.",
+	"category.recycle_bin":         "Recycle Bin",
+	"category.recycle_bin.desc":    "Files in the Recycle Bin are deleted permanently and cannot be restored. Make sure nothing there is still needed.",
+	"category.prefetch":            "Windows Prefetch",
+	"category.prefetch.desc":       "Records Windows uses to speed up program start. Startup is slower until they are rebuilt; cleaning is rarely useful.",
+
+	// Clean recommendations
+	"recommend.too_small":        "Very little to clean",
+	"recommend.high_risk":        "High risk: select manually and confirm",
+	"recommend.recently_cleaned": "Cleaned %d days ago, suggested interval is %d days",
+	"recommend.low_risk":         "Low risk, safe to clean",
+	"recommend.low_disk":         "System drive is low on space",
+	"recommend.large":            "Takes up a lot of space",
+	"recommend.medium_small":     "Medium risk and small, not suggested for now",
+	"recommend.unknown_risk":     "Unknown risk level",
+
+	// Downloads analysis
+	"downloads.app_installed": "Program already installed: %s",
+	"downloads.old_installer": "Installer older than %d days",
+	"downloads.old_archive":   "Archive or disk image older than %d days",
+
+	// Scan comparison
+	"snapshot.none":          "No scans recorded yet",
+	"snapshot.not_found":     "Scan not found: %s",
+	"snapshot.no_previous":   "No earlier scan to compare with",
+	"snapshot.kind_mismatch": "Scans are of different kinds and cannot be compared",
+
+	// History and time
+	"history.never_cleaned":     "Never cleaned",
+	"history.migration_pending": "Data format upgrade failed; history recording is paused to keep old records: %v",
+	"time.just_now":             "just now",
+	"time.minutes_ago":          "%[1]d %[2]s ago",
+	"time.hours_minutes_ago":    "%[1]d %[2]s %[3]d %[4]s ago",
+	"time.days_hours_ago":       "%[1]d %[2]s %[3]d %[4]s ago",
+	"time.unit.day":             "day",
+	"time.unit.days":            "days",
+	"time.unit.hour":            "hour",
+	"time.unit.hours":           "hours",
+	"time.unit.minute":          "minute",
+	"time.unit.minutes":         "minutes",
+
+	// Settings
+	"settings.net_sample_interval":     "Traffic sampling interval (seconds)",
+	"settings.public_ip_cache_minutes": "Public IP cache time (minutes)",
+	"settings.mem_history_days":        "Memory optimization history retention (days)",
+	"settings.listening_port_min":      "Default lowest listening port",
+	"settings.large_file_limit":        "Number of large files to list",
+	"settings.cpu_sample_ms":           "CPU sampling time (milliseconds)",
+	"settings.port_watch_interval":     "Port watch polling interval (seconds)",
+	"settings.conn_sample_interval":    "Connection history sampling interval (seconds)",
+	"settings.net_minute_days":         "Per-minute traffic retention (days)",
+	"settings.net_hourly_days":         "Hourly traffic retention (days)",
+	"settings.conn_history_days":       "Connection history retention (days)",
+	"settings.out_of_range":            "%s must be between %d and %d",
+	"error.unsupported_locale":         "Unsupported language: %s",
+
+	// Process protection
+	"process.protected.self":      "Cannot end this application itself: %s (PID %d)",
+	"process.protected.system":    "%s (PID %d) is a core system process and cannot be ended",
+	"process.protected.critical":  "%s (PID %d) is a critical system process; ending it may crash the system or sign you out",
+	"process.protected.blocklist": "%s (PID %d) is on the do-not-kill list",
+	"process.protected.unknown":   "Cannot read the name of PID %[2]d, so it may be a critical system process",
+
+	// Port watchlist
+	"portwatch.invalid_port": "Port must be between 1 and 65535",
+	"portwatch.too_many":     "At most %d ports can be watched",
+
+	// Free ports
+	"freeport.invalid_range":     "Invalid port range: must be within 1-65535 with start not greater than end",
+	"freeport.invalid_proto":     "Unsupported protocol: %s",
+	"freeport.invalid_interface": "Network adapter not found: %s",
+	"freeport.no_address":        "The selected adapters have no bindable address",
+	"freeport.not_held":          "Port %d is not being held",
+
+	// Service fingerprinting
+	"fingerprint.unreachable": "Not reachable on loopback (it may listen on another adapter only)",
+
+	// Port forwarding
+	"forward.invalid_addr":  "Address must be host:port, got %s",
+	"forward.loop":          "Target %s points back at the listen address %s and would loop",
+	"forward.too_many":      "At most %d port forwards can run at once",
+	"forward.not_found":     "Port forward not found: %s",
+	"forward.listen_failed": "Cannot listen on %s: %v",
+
+	// Connection history
+	"connhistory.invalid_group": "Unsupported grouping: %s",
+
+	// Public IP
+	"publicip.bad_status":          "Service returned status %d",
+	"publicip.bad_response":        "Service did not return a valid IP address",
+	"publicip.unknown_provider":    "Unknown public IP service: %s",
+	"publicip.no_provider":         "Select at least one public IP service",
+	"publicip.custom_url_required": "A URL is required for the self-hosted service",
+	"publicip.invalid_url":         "Invalid service URL: %s",
+	"publicip.https_required":      "The self-hosted service URL must use HTTPS: %s",
+	"publicip.all_failed":          "All public IP services failed: %v",
+	"publicip.insecure_redirect":   "Service redirected to a non-HTTPS URL: %s",
+	"publicip.too_many_redirects":  "Stopped after %d redirects",
+	"privacy.outbound_disabled":    "Privacy mode is on; no outbound requests are made",
+
+	// System interfaces (errors returned by pkg/)
+	"datadir.same_dir":             "Source is the current data folder: %s",
+	"datadir.conflict":             "Files already exist in %s: %s",
+	"datadir.move_failed":          "Failed to move %s: %v",
+	"winapi.recycle_bin_info":      "Failed to read Recycle Bin info: %s",
+	"winapi.empty_recycle_bin":     "Failed to empty Recycle Bin: %s",
+	"winapi.installed_apps":        "Failed to read installed programs: %s",
+	"winapi.process_list_changing": "Could not list the processes using the file because the list kept changing; try again later",
+	"timeutil.invalid":             "Unrecognized time: %s",
+	"userdirs.not_found":           "User folder not found",
+
+	// Data budgets
+	"budget.invalid_amount":    "Data budget must be greater than 0",
+	"budget.invalid_start_day": "Billing cycle start day must be between 1 and 31",
+	"budget.negative_weight":   "Billing weights cannot be negative",
+
+	// GPU
+	"gpu.none_detected": "No graphics card detected",
+	"gpu.none":          "None",
+	"gpu.integrated":    "Integrated",
+	"gpu.discrete":      "Discrete",
+
+	// Export / import
+	"backup.read_clean":       "Failed to read clean history: %w",
+	"backup.read_memopt":      "Failed to read memory optimization history: %w",
+	"backup.read_net":         "Failed to read traffic history: %w",
+	"backup.read_netcfg":      "Failed to read network adapter settings: %w",
+	"backup.unknown_mode":     "Unknown import mode: %s",
+	"backup.open_failed":      "Cannot open archive: %w",
+	"backup.missing_manifest": "Archive has no %s, not a valid backup",
+	"backup.manifest_corrupt": "Manifest is corrupt: %w",
+	"backup.missing_entry":    "Archive is missing file: %s",
+	"backup.checksum":         "Checksum mismatch: %s",
+	"backup.parse_entry":      "Failed to parse %s: %w",
+	"backup.invalid_settings": "Settings in the archive are invalid: %w",
+	"backup.import_entry":     "Failed to import %s: %w",
+	"backup.commit_entry":     "Failed to write %s (earlier entries were imported): %w",
+	"backup.upgrade_failed":   "Failed to convert backup from data format version %d: %w",
+	"backup.invalid_archive":  "Not a valid backup file",
+	"backup.newer_format":     "Backup format version %d is newer than supported version %d, please upgrade first",
+	"backup.newer_schema":     "Backup data format version %d is newer than current version %d; please update the app first",
+	"backup.entry_too_large":  "File too large: %s",
+	"backup.read_entry":       "Failed to read %s: %w",
+	"csv.unknown_kind":        "Unknown history type: %s",
+	"csv.time":                "Time",
+	"csv.date":                "Date",
+	"csv.zone":                "Time zone",
+	"csv.month":               "Month",
+	"csv.freed_bytes":         "Freed bytes",
+	"csv.freed_mb":            "Freed (MB)",
+	"csv.cleaned_count":       "Files cleaned",
+	"csv.categories":          "Categories",
+	"csv.before_percent":      "Usage before (%)",
+	"csv.after_percent":       "Usage after (%)",
+	"csv.iface":               "Adapter",
+	"csv.sent_bytes":          "Sent bytes",
+	"csv.recv_bytes":          "Received bytes",
+	"dialog.export_title":     "Export data",
+	"dialog.import_title":     "Import data",
+	"dialog.csv_title":        "Export CSV",
+	"dialog.backup_filter":    "Backup files (*.zip)",
+	"dialog.csv_filter":       "CSV files (*.csv)",
+
+	// Update check
+	"update.request_failed": "Request to GitHub failed: %w",
+	"update.bad_status":     "GitHub API returned %d",
+	"update.parse_failed":   "Failed to parse response: %w",
+
+	// Command line
+	"cli.flag_data_dir":     "data directory (overrides %s and portable mode)",
+	"cli.flag_migrate":      "move existing data into the current data directory and exit",
+	"cli.flag_migrate_from": "source directory to migrate from (default ~/.wincleaner)",
+	"cli.no_default_dir":    "Cannot determine default data directory:",
+	"cli.migrate_failed":    "Migration failed:",
+	"cli.migrated":          "Moved %d files from %s to %s\n",
+}
@@ -0,0 +1,189 @@
+package i18n
+
+var zhCN = map[string]string{
+	// 垃圾分类
+	"category.temp":                "系统临时文件",
+	"category.temp.desc":           "程序运行时留下的临时文件，正在使用的文件会被自动跳过。",
+	"category.windows_update":      "Windows Update 缓存",
+	"category.windows_update.desc": "已下载的系统更新安装包。更新安装完成后可以删除；若有更新正在等待安装，清理后需要重新下载。",
+	"category.thumbnails":          "缩略图缓存",
+	"category.thumbnails.desc":     "资源管理器的图片预览缓存，清理后首次打开文件夹时会重新生成。",
+	"category.system_logs":         "系统日志",
+	"category.system_logs.desc":    "系统组件的运行日志。日常使用用不到，但排查系统问题时会丢失历史记录。",
+	"category.browser_cache":       "浏览器缓存",
+	"category.browser_cache.desc":  "网页图片和脚本的缓存，不影响登录状态和书签，清理后网页首次打开会稍慢。",
+	"category.recycle_bin":         "回收站",
+	"category.recycle_bin.desc":    "回收站中的文件会被永久删除，无法再还原，请确认其中没有需要的文件。",
+	"category.prefetch":            "Windows 预读取",
+	"category.prefetch.desc":       "系统用来加速程序启动的记录。清理后开机和程序启动会暂时变慢，系统会自动重建，通常没有必要清理。",
+
+	// 清理推荐
+	"recommend.too_small":        "可清理内容很少",
+	"recommend.high_risk":        "高风险分类，需手动选择并二次确认",
+	"recommend.recently_cleaned": "%d 天前刚清理过，建议间隔 %d 天",
+	"recommend.low_risk":         "低风险，可放心清理",
+	"recommend.low_disk":         "系统盘空间不足",
+	"recommend.large":            "占用空间较大",
+	"recommend.medium_small":     "中风险且占用较小，暂不建议",
+	"recommend.unknown_risk":     "未知风险等级",
+
+	// 下载目录分析
+	"downloads.app_installed": "对应程序已安装：%s",
+	"downloads.old_installer": "超过 %d 天的安装包",
+	"downloads.old_archive":   "超过 %d 天的压缩包/镜像",
+
+	// 扫描对比
+	"snapshot.none":          "暂无扫描记录",
+	"snapshot.not_found":     "扫描记录不存在: %s",
+	"snapshot.no_previous":   "没有可对比的历史扫描",
+	"snapshot.kind_mismatch": "扫描类型不同，无法对比",
+
+	// 历史与时间
+	"history.never_cleaned":     "从未清理",
+	"history.migration_pending": "数据格式升级失败，暂停记录历史以免丢失旧记录：%v",
+	"time.just_now":             "刚刚",
+	"time.minutes_ago":          "%[1]d分钟前",
+	"time.hours_minutes_ago":    "%[1]d小时%[3]d分钟前",
+	"time.days_hours_ago":       "%[1]d天%[3]d小时前",
+	"time.unit.day":             "天",
+	"time.unit.days":            "天",
+	"time.unit.hour":            "小时",
+	"time.unit.hours":           "小时",
+	"time.unit.minute":          "分钟",
+	"time.unit.minutes":         "分钟",
+
+	// 设置
+	"settings.net_sample_interval":     "流量采样间隔（秒）",
+	"settings.public_ip_cache_minutes": "公网 IP 缓存时间（分钟）",
+	"settings.mem_history_days":        "内存优化历史保留天数",
+	"settings.listening_port_min":      "监听端口默认起始端口",
+	"settings.large_file_limit":        "大文件扫描数量",
+	"settings.cpu_sample_ms":           "CPU 采样时长（毫秒）",
+	"settings.port_watch_interval":     "关注端口轮询间隔（秒）",
+	"settings.conn_sample_interval":    "连接历史采样间隔（秒）",
+	"settings.net_minute_days":         "分钟级流量记录保留天数",
+	"settings.net_hourly_days":         "小时级流量记录保留天数",
+	"settings.conn_history_days":       "连接历史保留天数",
+	"settings.out_of_range":            "%s须在 %d-%d 之间",
+	"error.unsupported_locale":         "不支持的语言: %s",
+
+	// 进程保护
+	"process.protected.self":      "不能结束本程序自身：%s (PID %d)",
+	"process.protected.system":    "%s (PID %d) 是系统核心进程，不能结束",
+	"process.protected.critical":  "%s (PID %d) 是系统关键进程，结束后可能导致系统崩溃或注销",
+	"process.protected.blocklist": "%s (PID %d) 在禁止结束列表中",
+	"process.protected.unknown":   "无法读取 PID %[2]d 的进程名，不能确认它不是系统关键进程",
+
+	// 端口关注
+	"portwatch.invalid_port": "端口号须在 1-65535 之间",
+	"portwatch.too_many":     "最多关注 %d 个端口",
+
+	// 空闲端口
+	"freeport.invalid_range":     "端口范围无效，须在 1-65535 之间且起始不大于结束",
+	"freeport.invalid_proto":     "不支持的协议: %s",
+	"freeport.invalid_interface": "找不到网卡: %s",
+	"freeport.no_address":        "所选网卡没有可绑定的地址",
+	"freeport.not_held":          "端口 %d 未被占住",
+
+	// 服务识别
+	"fingerprint.unreachable": "无法通过回环地址连接（可能只监听了其他网卡地址）",
+
+	// 端口转发
+	"forward.invalid_addr":  "地址格式应为 主机:端口，当前为 %s",
+	"forward.loop":          "目标地址 %s 指向监听地址 %s 本身，会形成转发回环",
+	"forward.too_many":      "最多同时运行 %d 条端口转发",
+	"forward.not_found":     "端口转发不存在: %s",
+	"forward.listen_failed": "无法监听 %s: %v",
+
+	// 连接历史
+	"connhistory.invalid_group": "不支持的分组方式: %s",
+
+	// 公网 IP
+	"publicip.bad_status":          "服务返回状态码 %d",
+	"publicip.bad_response":        "服务返回的不是有效的 IP 地址",
+	"publicip.unknown_provider":    "未知的公网 IP 服务: %s",
+	"publicip.no_provider":         "至少需要选择一个公网 IP 服务",
+	"publicip.custom_url_required": "使用自建服务时需要填写服务地址",
+	"publicip.invalid_url":         "服务地址无效: %s",
+	"publicip.https_required":      "自建服务地址必须使用 HTTPS: %s",
+	"publicip.all_failed":          "所有公网 IP 服务均查询失败: %v",
+	"publicip.insecure_redirect":   "服务重定向到了非 HTTPS 地址: %s",
+	"publicip.too_many_redirects":  "重定向次数超过 %d 次",
+	"privacy.outbound_disabled":    "隐私模式已开启，不发出外网请求",
+
+	// 系统接口（pkg/ 返回的错误）
+	"datadir.same_dir":             "源目录与当前数据目录相同: %s",
+	"datadir.conflict":             "目标目录 %s 已存在同名文件: %s",
+	"datadir.move_failed":          "移动 %s 失败: %v",
+	"winapi.recycle_bin_info":      "获取回收站信息失败: %s",
+	"winapi.empty_recycle_bin":     "清空回收站失败: %s",
+	"winapi.installed_apps":        "读取已安装程序失败: %s",
+	"winapi.process_list_changing": "查询占用文件的进程失败：进程列表持续变化，请稍后重试",
+	"timeutil.invalid":             "无法解析的时间: %s",
+	"userdirs.not_found":           "未找到用户目录",
+
+	// 流量套餐
+	"budget.invalid_amount":    "流量额度必须大于 0",
+	"budget.invalid_start_day": "计费周期起始日须在 1-31 之间",
+	"budget.negative_weight":   "计费权重不能为负数",
+
+	// 显卡
+	"gpu.none_detected": "未检测到显卡",
+	"gpu.none":          "无显卡",
+	"gpu.integrated":    "核显",
+	"gpu.discrete":      "独显",
+
+	// 导出/导入
+	"backup.read_clean":       "读取清理历史失败: %w",
+	"backup.read_memopt":      "读取内存优化历史失败: %w",
+	"backup.read_net":         "读取流量历史失败: %w",
+	"backup.read_netcfg":      "读取网卡设置失败: %w",
+	"backup.unknown_mode":     "未知的导入模式: %s",
+	"backup.open_failed":      "无法打开归档: %w",
+	"backup.missing_manifest": "归档缺少 %s，不是有效的备份文件",
+	"backup.manifest_corrupt": "清单损坏: %w",
+	"backup.missing_entry":    "归档缺少文件: %s",
+	"backup.checksum":         "文件校验失败: %s",
+	"backup.parse_entry":      "解析 %s 失败: %w",
+	"backup.invalid_settings": "归档中的设置无效: %w",
+	"backup.import_entry":     "导入 %s 失败: %w",
+	"backup.commit_entry":     "写入 %s 失败（之前的项已导入）: %w",
+	"backup.upgrade_failed":   "转换数据格式版本 %d 的备份失败: %w",
+	"backup.invalid_archive":  "不是有效的备份文件",
+	"backup.newer_format":     "备份文件格式版本 %d 高于当前程序支持的版本 %d，请先升级程序",
+	"backup.newer_schema":     "备份的数据格式版本 %d 高于当前版本 %d，请先升级程序",
+	"backup.entry_too_large":  "文件过大: %s",
+	"backup.read_entry":       "读取 %s 失败: %w",
+	"csv.unknown_kind":        "未知的历史类型: %s",
+	"csv.time":                "时间",
+	"csv.date":                "日期",
+	"csv.zone":                "时区",
+	"csv.month":               "月份",
+	"csv.freed_bytes":         "释放字节",
+	"csv.freed_mb":            "释放(MB)",
+	"csv.cleaned_count":       "清理文件数",
+	"csv.categories":          "分类明细",
+	"csv.before_percent":      "优化前占用(%)",
+	"csv.after_percent":       "优化后占用(%)",
+	"csv.iface":               "网卡",
+	"csv.sent_bytes":          "上传字节",
+	"csv.recv_bytes":          "下载字节",
+	"dialog.export_title":     "导出数据",
+	"dialog.import_title":     "导入数据",
+	"dialog.csv_title":        "导出 CSV",
+	"dialog.backup_filter":    "备份文件 (*.zip)",
+	"dialog.csv_filter":       "CSV 文件 (*.csv)",
+
+	// 检查更新
+	"update.request_failed": "请求 GitHub 失败: %w",
+	"update.bad_status":     "GitHub API 返回 %d",
+	"update.parse_failed":   "解析响应失败: %w",
+
+	// 命令行
+	"cli.flag_data_dir":     "数据目录（优先于环境变量 %s 和便携模式）",
+	"cli.flag_migrate":      "把已有数据移动到当前数据目录后退出",
+	"cli.flag_migrate_from": "迁移的源目录（默认 ~/.wincleaner）",
+	"cli.no_default_dir":    "无法确定默认数据目录:",
+	"cli.migrate_failed":    "迁移失败:",
+	"cli.migrated":          "已将 %d 个文件从 %s 移动到 %s\n",
+}
@@ -1,67 +1,55 @@
 package memory
 
 import (
-	"encoding/json"
-	"os"
 	"sort"
 	"strconv"
 	"time"
 
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/migration"
 	"win-cleaner/internal/model"
-	"win-cleaner/pkg/datadir"
+	"win-cleaner/internal/settings"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
 )
 
-func memHistoryPath() string {
-	return datadir.FilePath("mem_opt_history.json")
-}
+const memHistoryFile = "mem_opt_history.json"
 
 // RecordOptimize 记录一次优化
 func RecordOptimize(result *model.MemoryOptResult) error {
-	filePath := memHistoryPath()
-
-	var history model.MemOptHistory
-	data, err := os.ReadFile(filePath)
-	if err == nil {
-		_ = json.Unmarshal(data, &history)
+	if err := migration.Err(); err != nil {
+		return i18n.Errorf("history.migration_pending", err)
 	}
-
 	now := time.Now()
 	record := model.MemOptRecord{
-		Date:          now.Format("2006-01-02"),
-		Time:          now.Format("15:04:05"),
+		Timestamp:     timeutil.Format(now),
 		FreedMB:       result.FreedMB,
 		BeforePercent: result.BeforePercent,
 		AfterPercent:  result.AfterPercent,
 	}
-	history.Records = append(history.Records, record)
 
-	// 只保留最近 90 天
-	cutoff := now.AddDate(0, 0, -90).Format("2006-01-02")
-	var filtered []model.MemOptRecord
-	for _, r := range history.Records {
-		if r.Date >= cutoff {
-			filtered = append(filtered, r)
+	var history model.MemOptHistory
+	return storage.Update(memHistoryFile, &history, func() error {
+		history.Records = append(history.Records, record)
+
+		// 只保留设置的天数（默认 90 天）
+		cutoff := now.AddDate(0, 0, -settings.Get().MemHistoryDays)
+		var filtered []model.MemOptRecord
+		for _, r := range history.Records {
+			if at, err := timeutil.Parse(r.Timestamp); err != nil || !at.Before(cutoff) {
+				filtered = append(filtered, r)
+			}
 		}
-	}
-	history.Records = filtered
-
-	out, err := json.MarshalIndent(history, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(memHistoryPath(), out, 0644)
+		history.Records = filtered
+		return nil
+	})
 }
 
 // GetMemOptStats 获取优化历史统计
 func GetMemOptStats() (*model.MemOptStats, error) {
 	var history model.MemOptHistory
-	data, err := os.ReadFile(memHistoryPath())
-	if err != nil {
-		// 没有历史记录，返回空
-		return &model.MemOptStats{}, nil
-	}
-	if err := json.Unmarshal(data, &history); err != nil {
-		return &model.MemOptStats{}, nil
+	if err := storage.Load(memHistoryFile, &history); err != nil {
+		return nil, err
 	}
 
 	stats := &model.MemOptStats{}
@@ -77,50 +65,34 @@ func GetMemOptStats() (*model.MemOptStats, error) {
 
 	// 上次优化
 	last := history.Records[len(history.Records)-1]
-	stats.LastOptTime = last.Date + " " + last.Time
-	lastTime, err := time.Parse("2006-01-02 15:04:05", stats.LastOptTime)
-	if err == nil {
-		dur := time.Since(lastTime)
-		if dur.Hours() < 1 {
-			stats.LastOptAgo = "刚刚"
-		} else if dur.Hours() < 24 {
-			hours := int(dur.Hours())
-			stats.LastOptAgo = strconv.Itoa(hours) + " 小时前"
-		} else {
-			days := int(dur.Hours() / 24)
-			stats.LastOptAgo = strconv.Itoa(days) + " 天前"
-		}
+	stats.LastOptTime = timeutil.Display(last.Timestamp)
+	if lastTime, err := timeutil.Parse(last.Timestamp); err == nil {
+		stats.LastOptAgo = i18n.RelativeTime(time.Since(lastTime))
 	}
 
-	// 按天汇总（近 30 天）
+	// 按天（近 30 天）、按月汇总，日期取优化时所在时区
 	dailyMap := make(map[string]*model.MemOptDailyStat)
-	cutoff30 := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+	monthlyMap := make(map[string]*model.MemOptMonthlyStat)
+	cutoff30 := timeutil.Day(time.Now().AddDate(0, 0, -30))
 	for _, r := range history.Records {
-		if r.Date < cutoff30 {
+		at, err := timeutil.Parse(r.Timestamp)
+		if err != nil {
 			continue
 		}
-		if d, ok := dailyMap[r.Date]; ok {
-			d.FreedMB += r.FreedMB
-			d.Count++
-		} else {
-			dailyMap[r.Date] = &model.MemOptDailyStat{
-				Date:    r.Date,
-				FreedMB: r.FreedMB,
-				Count:   1,
+		if day := timeutil.Day(at); day >= cutoff30 {
+			if d, ok := dailyMap[day]; ok {
+				d.FreedMB += r.FreedMB
+				d.Count++
+			} else {
+				dailyMap[day] = &model.MemOptDailyStat{
+					Date:    day,
+					FreedMB: r.FreedMB,
+					Count:   1,
+				}
 			}
 		}
-	}
-	for _, d := range dailyMap {
-		stats.DailyStats = append(stats.DailyStats, *d)
-	}
-	sort.Slice(stats.DailyStats, func(i, j int) bool {
-		return stats.DailyStats[i].Date < stats.DailyStats[j].Date
-	})
 
-	// 按月汇总
-	monthlyMap := make(map[string]*model.MemOptMonthlyStat)
-	for _, r := range history.Records {
-		month := r.Date[:7]
+		month := timeutil.Month(at)
 		if m, ok := monthlyMap[month]; ok {
 			m.FreedMB += r.FreedMB
 			m.Count++
@@ -132,6 +104,13 @@ func GetMemOptStats() (*model.MemOptStats, error) {
 			}
 		}
 	}
+	for _, d := range dailyMap {
+		stats.DailyStats = append(stats.DailyStats, *d)
+	}
+	sort.Slice(stats.DailyStats, func(i, j int) bool {
+		return stats.DailyStats[i].Date < stats.DailyStats[j].Date
+	})
+
 	for _, m := range monthlyMap {
 		stats.MonthlyStats = append(stats.MonthlyStats, *m)
 	}
@@ -148,3 +127,61 @@ func GetMemOptStats() (*model.MemOptStats, error) {
 
 	return stats, nil
 }
+
+// ExportMemOptRecords 导出全部内存优化记录
+func ExportMemOptRecords() ([]model.MemOptRecord, error) {
+	var history model.MemOptHistory
+	if err := storage.Load(memHistoryFile, &history); err != nil {
+		return nil, err
+	}
+	return history.Records, nil
+}
+
+// StageMemOptImport 读取本地记录并计算导入结果，不修改文件；返回的 commit 执行写入
+//
+// replace 为 true 时替换本地全部记录；否则合并，时间和释放量都相同的记录视为重复。
+// commit 按写入时的文件重新合并，其间新增的本地记录不会丢失。
+func StageMemOptImport(records []model.MemOptRecord, replace bool) (added, skipped int, commit func() error, err error) {
+	var history model.MemOptHistory
+	if err := storage.Load(memHistoryFile, &history); err != nil {
+		return 0, 0, nil, err
+	}
+	added, skipped = mergeMemOptRecords(&history, records, replace)
+	commit = func() error {
+		var history model.MemOptHistory
+		return storage.Update(memHistoryFile, &history, func() error {
+			mergeMemOptRecords(&history, records, replace)
+			return nil
+		})
+	}
+	return added, skipped, commit, nil
+}
+
+// mergeMemOptRecords 把导入的记录并入 history 并按时间排序
+func mergeMemOptRecords(history *model.MemOptHistory, records []model.MemOptRecord, replace bool) (added, skipped int) {
+	if replace {
+		history.Records = nil
+	}
+	seen := make(map[string]bool, len(history.Records))
+	for _, r := range history.Records {
+		seen[memRecordKey(r)] = true
+	}
+	for _, r := range records {
+		key := memRecordKey(r)
+		if seen[key] {
+			skipped++
+			continue
+		}
+		seen[key] = true
+		history.Records = append(history.Records, r)
+		added++
+	}
+	sort.SliceStable(history.Records, func(i, j int) bool {
+		return timeutil.Before(history.Records[i].Timestamp, history.Records[j].Timestamp)
+	})
+	return added, skipped
+}
+
+func memRecordKey(r model.MemOptRecord) string {
+	return r.Timestamp + "|" + strconv.FormatFloat(r.FreedMB, 'f', 2, 64)
+}
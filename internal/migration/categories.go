@@ -0,0 +1,67 @@
+package migration
+
+import "encoding/json"
+
+// 版本 2：垃圾分类从中文名称改为稳定的分类 ID
+//
+//   - clean_history.json：records[].categories[].category
+//   - scan_snapshots.json：snapshots[].categories[].category 及 top_dirs[].category
+//
+// 不在对照表中的值（包括已是 ID 的）保持不变，因此重复执行是安全的。
+
+// legacyCategoryIDs 旧版分类名称 → 分类 ID
+var legacyCategoryIDs = map[string]string{
+	"系统临时文件":            "temp",
+	"Windows Update 缓存": "windows_update",
+	"缩略图缓存":             "thumbnails",
+	"系统日志":              "system_logs",
+	"浏览器缓存":             "browser_cache",
+	"回收站":               "recycle_bin",
+	"Windows 预读取":       "prefetch",
+}
+
+func migrateCategoryIDs() error {
+	if err := convertJSONList("clean_history.json", "records", func(r rawRecord) {
+		convertNested(r, "categories", convertCategory)
+	}); err != nil {
+		return err
+	}
+	return convertJSONList("scan_snapshots.json", "snapshots", func(r rawRecord) {
+		convertNested(r, "categories", func(c rawRecord) {
+			convertCategory(c)
+			convertNested(c, "top_dirs", convertCategory)
+		})
+	})
+}
+
+// archiveCategoryIDs 导出归档：清理记录中的分类
+func archiveCategoryIDs(entries map[string][]byte) error {
+	return convertArchiveEntry(entries, "clean_history.json", "records", func(r rawRecord) {
+		convertNested(r, "categories", convertCategory)
+	})
+}
+
+// convertCategory 把 category 字段中的旧名称换成 ID
+func convertCategory(r rawRecord) {
+	if id, ok := legacyCategoryIDs[stringField(r, "category")]; ok {
+		setString(r, "category", id)
+	}
+}
+
+// convertNested 转换记录中 key 对应的对象数组，字段不存在或格式不符时跳过
+func convertNested(r rawRecord, key string, fn func(rawRecord)) {
+	data, ok := r[key]
+	if !ok {
+		return
+	}
+	var list []rawRecord
+	if err := json.Unmarshal(data, &list); err != nil || list == nil {
+		return
+	}
+	for _, item := range list {
+		fn(item)
+	}
+	if data, err := json.Marshal(list); err == nil {
+		r[key] = data
+	}
+}
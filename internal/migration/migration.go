@@ -0,0 +1,85 @@
+// Package migration 数据目录的版本化格式迁移，程序启动时执行
+//
+// 每个迁移只处理它所在版本时的文件格式，文件名和字段名在这里单独写死，
+// 不引用各业务包中的常量，避免日后改名后旧迁移随之改变。
+package migration
+
+import (
+	"encoding/json"
+	"sync"
+
+	"win-cleaner/pkg/storage"
+)
+
+var migrations = []storage.Migration{
+	{Version: 1, Name: "rfc3339_timestamps", Apply: migrateTimestamps},
+	{Version: 2, Name: "category_ids", Apply: migrateCategoryIDs},
+}
+
+// archiveMigrations 各版本对导出归档内容（文件名 → JSON）的转换，不涉及归档文件的版本没有对应项
+//
+// 导出归档中各文件的记录格式与同版本数据目录中的一致，归档文件名同样在这里单独写死。
+var archiveMigrations = map[int]func(entries map[string][]byte) error{
+	1: archiveTimestamps,
+	2: archiveCategoryIDs,
+}
+
+var (
+	runErr error
+	runMu  sync.Mutex
+)
+
+// Run 执行所有尚未完成的迁移，结果由 Err 返回
+func Run() error {
+	err := storage.Migrate(migrations)
+	runMu.Lock()
+	runErr = err
+	runMu.Unlock()
+	return err
+}
+
+// UpgradeArchive 把数据格式版本为 from 的导出归档内容就地转换为当前格式
+func UpgradeArchive(entries map[string][]byte, from int) error {
+	for _, m := range migrations {
+		if m.Version <= from {
+			continue
+		}
+		if fn, ok := archiveMigrations[m.Version]; ok {
+			if err := fn(entries); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// convertArchiveEntry 转换归档文件中 listKey 数组的每一项，文件不存在时跳过
+func convertArchiveEntry(entries map[string][]byte, name, listKey string, fn func(rawRecord)) error {
+	data, ok := entries[name]
+	if !ok {
+		return nil
+	}
+	var top rawRecord
+	if err := json.Unmarshal(data, &top); err != nil {
+		return err
+	}
+	if err := convertList(top, listKey, fn); err != nil {
+		return err
+	}
+	data, err := json.Marshal(top)
+	if err != nil {
+		return err
+	}
+	entries[name] = data
+	return nil
+}
+
+// Err 返回上次 Run 的错误
+//
+// 迁移失败时旧格式文件尚未转换，按新结构改写会丢掉旧字段（如 date/time），
+// 改写这类文件前需先检查；数据保持原样，下次启动重试。
+func Err() error {
+	runMu.Lock()
+	defer runMu.Unlock()
+	return runErr
+}
@@ -0,0 +1,247 @@
+package migration
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/datadir"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+)
+
+// useFixture 把 testdata 下的旧版数据复制到临时数据目录
+func useFixture(t *testing.T, fixture string) string {
+	t.Helper()
+	dir := t.TempDir()
+	src := filepath.Join("testdata", fixture)
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(src, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, e.Name()), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	datadir.SetOverride(dir)
+	t.Cleanup(func() { datadir.SetOverride("") })
+	return dir
+}
+
+func localStamp(t *testing.T, s string) string {
+	t.Helper()
+	at, err := time.ParseInLocation("2006-01-02 15:04:05", s, time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return timeutil.Format(at)
+}
+
+func TestRunLegacyFixture(t *testing.T) {
+	dir := useFixture(t, "v0")
+
+	if err := Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if err := Err(); err != nil {
+		t.Fatalf("Err after successful Run: %v", err)
+	}
+	if v, err := storage.SchemaVersion(); err != nil || v != migrations[len(migrations)-1].Version {
+		t.Fatalf("SchemaVersion = %d, %v", v, err)
+	}
+
+	// 清理历史：date + time → timestamp，旧字段去掉，分类名称换成 ID，其余字段保留
+	var clean struct {
+		Records []rawRecord `json:"records"`
+	}
+	if err := storage.Load("clean_history.json", &clean); err != nil {
+		t.Fatal(err)
+	}
+	if len(clean.Records) != 2 {
+		t.Fatalf("clean records = %d, want 2", len(clean.Records))
+	}
+	first := clean.Records[0]
+	if got, want := stringField(first, "timestamp"), localStamp(t, "2024-03-05 14:30:00"); got != want {
+		t.Errorf("clean timestamp = %q, want %q", got, want)
+	}
+	for _, key := range []string{"date", "time"} {
+		if _, ok := first[key]; ok {
+			t.Errorf("clean record still has %q", key)
+		}
+	}
+	if string(first["freed_size"]) != "1048576" {
+		t.Errorf("freed_size = %s", first["freed_size"])
+	}
+	var typed model.CleanHistory
+	if err := storage.Load("clean_history.json", &typed); err != nil {
+		t.Fatal(err)
+	}
+	cats := typed.Records[0].Categories
+	if len(cats) != 2 || cats[0].Category != "temp" || cats[1].Category != "browser_cache" {
+		t.Errorf("categories = %+v", cats)
+	}
+	if got, want := typed.Records[1].Timestamp, localStamp(t, "2024-03-06 09:05:07"); got != want {
+		t.Errorf("second clean timestamp = %q, want %q", got, want)
+	}
+
+	// 内存优化历史
+	var mem model.MemOptHistory
+	if err := storage.Load("mem_opt_history.json", &mem); err != nil {
+		t.Fatal(err)
+	}
+	if len(mem.Records) != 1 || mem.Records[0].Timestamp != localStamp(t, "2024-03-05 14:31:10") || mem.Records[0].FreedMB != 256.5 {
+		t.Errorf("mem records = %+v", mem.Records)
+	}
+
+	// 旧版流量历史导入分钟日志，原文件改名保留
+	minute, err := storage.ReadRecords[model.NetTrafficRecord]("net_minute.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(minute) != 2 || minute[0].Timestamp != localStamp(t, "2024-03-05 14:30:00") || minute[1].Recv != 6000 {
+		t.Errorf("net minute records = %+v", minute)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "net_history.json.migrated")); err != nil {
+		t.Errorf("legacy net history not renamed: %v", err)
+	}
+
+	// 扫描摘要：时间与分类
+	var snaps model.ScanSnapshotHistory
+	if err := storage.Load("scan_snapshots.json", &snaps); err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps.Snapshots) != 1 {
+		t.Fatalf("snapshots = %d, want 1", len(snaps.Snapshots))
+	}
+	s := snaps.Snapshots[0]
+	if s.Time != localStamp(t, "2024-03-05 14:30:00") {
+		t.Errorf("snapshot time = %q", s.Time)
+	}
+	if c := s.Categories[0]; c.Category != "temp" || c.TopDirs[0].Category != "temp" {
+		t.Errorf("snapshot category = %+v", c)
+	}
+}
+
+func TestMigrationsRepeatable(t *testing.T) {
+	dir := useFixture(t, "v0")
+	if err := Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	names := []string{"clean_history.json", "mem_opt_history.json", "scan_snapshots.json", "net_minute.jsonl"}
+	before := make(map[string][]byte)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		before[name] = data
+	}
+
+	// 中途崩溃时会重新执行已完成的步骤，结果必须不变
+	for _, m := range migrations {
+		if err := m.Apply(); err != nil {
+			t.Fatalf("%s: %v", m.Name, err)
+		}
+	}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bytes.TrimSpace(data), bytes.TrimSpace(before[name])) {
+			t.Errorf("%s changed on re-run:\n%s\n---\n%s", name, before[name], data)
+		}
+	}
+}
+
+func TestRunFailureReported(t *testing.T) {
+	dir := useFixture(t, "v0")
+	// 无法读取的历史文件：迁移失败，原数据保持不变
+	path := filepath.Join(dir, "clean_history.json")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { runErr = nil })
+
+	if err := Run(); err == nil {
+		t.Fatal("Run succeeded on unreadable history")
+	}
+	if Err() == nil {
+		t.Fatal("Err() = nil after failed Run")
+	}
+	if v, _ := storage.SchemaVersion(); v != 0 {
+		t.Errorf("SchemaVersion = %d after failure, want 0", v)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "mem_opt_history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte(`"date": "2024-03-05"`)) {
+		t.Errorf("mem history rewritten after failed migration:\n%s", data)
+	}
+}
+
+func TestUpgradeArchive(t *testing.T) {
+	entries := map[string][]byte{
+		"clean_history.json": []byte(`{"records":[{"date":"2024-03-05","time":"14:30:00","freed_size":1,"cleaned_count":1,"categories":[{"category":"回收站","freed_size":1,"cleaned_count":1}]}]}`),
+		"net_traffic.json":   []byte(`{"minute":[{"timestamp":"2024-03-05 14:30","date":"2024-03-05","sent":1,"recv":2}],"hourly":[],"daily":null}`),
+		"settings.json":      []byte(`{"net_sample_interval":30}`),
+	}
+	settingsBefore := string(entries["settings.json"])
+
+	if err := UpgradeArchive(entries, 0); err != nil {
+		t.Fatalf("UpgradeArchive: %v", err)
+	}
+
+	var clean struct {
+		Records []rawRecord `json:"records"`
+	}
+	if err := json.Unmarshal(entries["clean_history.json"], &clean); err != nil {
+		t.Fatal(err)
+	}
+	r := clean.Records[0]
+	if got, want := stringField(r, "timestamp"), localStamp(t, "2024-03-05 14:30:00"); got != want {
+		t.Errorf("timestamp = %q, want %q", got, want)
+	}
+	if _, ok := r["date"]; ok {
+		t.Error("date not removed")
+	}
+	if !bytes.Contains(r["categories"], []byte(`"recycle_bin"`)) {
+		t.Errorf("categories = %s", r["categories"])
+	}
+
+	var net struct {
+		Minute []rawRecord `json:"minute"`
+	}
+	if err := json.Unmarshal(entries["net_traffic.json"], &net); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stringField(net.Minute[0], "timestamp"), localStamp(t, "2024-03-05 14:30:00"); got != want {
+		t.Errorf("net timestamp = %q, want %q", got, want)
+	}
+	if string(entries["settings.json"]) != settingsBefore {
+		t.Errorf("settings changed: %s", entries["settings.json"])
+	}
+
+	// 已是当前版本的归档不做转换
+	current := map[string][]byte{"clean_history.json": []byte(`{"records":[{"category":"回收站"}]}`)}
+	if err := UpgradeArchive(current, migrations[len(migrations)-1].Version); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(current["clean_history.json"], []byte("回收站")) {
+		t.Errorf("current archive converted: %s", current["clean_history.json"])
+	}
+}
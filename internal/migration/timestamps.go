@@ -0,0 +1,218 @@
+package migration
+
+import (
+	"encoding/json"
+	"os"
+
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+)
+
+// 版本 1：历史记录的时间从不带时区的本地时间字符串改为 RFC 3339 时间戳
+//
+//   - clean_history.json / mem_opt_history.json：date + time → timestamp
+//   - net_minute/hourly/daily.jsonl 及旧版 net_history.json：timestamp（YYYY-MM-DD HH:MM）+ date → timestamp
+//   - net_gaps.jsonl 的 start/end、net_baseline.json 与 scan_snapshots.json 的 time
+//
+// 旧时间按本机时区解析，夏令时按当时的规则确定偏移。
+// 已是 RFC 3339 的字段保持不变，因此重复执行是安全的。JSON Lines 日志改写前备份为 name.v0。
+
+// rawRecord 以原始 JSON 保留未涉及的字段
+type rawRecord = map[string]json.RawMessage
+
+func migrateTimestamps() error {
+	for _, name := range []string{"clean_history.json", "mem_opt_history.json"} {
+		if err := convertJSONList(name, "records", convertDateTime); err != nil {
+			return err
+		}
+	}
+	if err := convertJSONList("scan_snapshots.json", "snapshots", convertFields("time")); err != nil {
+		return err
+	}
+	if err := convertJSONObject("net_baseline.json", convertFields("time")); err != nil {
+		return err
+	}
+	for _, name := range []string{"net_minute.jsonl", "net_hourly.jsonl", "net_daily.jsonl"} {
+		if err := convertLog(name, convertNetRecord); err != nil {
+			return err
+		}
+	}
+	if err := convertLog("net_gaps.jsonl", convertFields("start", "end")); err != nil {
+		return err
+	}
+	return importLegacyNetHistory()
+}
+
+// archiveTimestamps 导出归档：清理/内存优化记录与三级流量记录
+func archiveTimestamps(entries map[string][]byte) error {
+	for _, name := range []string{"clean_history.json", "mem_opt_history.json"} {
+		if err := convertArchiveEntry(entries, name, "records", convertDateTime); err != nil {
+			return err
+		}
+	}
+	for _, tier := range []string{"minute", "hourly", "daily"} {
+		if err := convertArchiveEntry(entries, "net_traffic.json", tier, convertNetRecord); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertDateTime 清理/内存优化记录：date + time → timestamp
+func convertDateTime(r rawRecord) {
+	if timeutil.IsRFC3339(stringField(r, "timestamp")) {
+		return
+	}
+	date, clock := stringField(r, "date"), stringField(r, "time")
+	t, err := timeutil.Parse(date + " " + clock)
+	if err != nil {
+		return
+	}
+	setString(r, "timestamp", timeutil.Format(t))
+	delete(r, "date")
+	delete(r, "time")
+}
+
+// convertNetRecord 流量记录：timestamp（汇总记录可能只有日期或小时）→ RFC 3339，去掉 date
+func convertNetRecord(r rawRecord) {
+	ts := stringField(r, "timestamp")
+	if timeutil.IsRFC3339(ts) {
+		return
+	}
+	t, err := timeutil.Parse(ts)
+	if err != nil {
+		if t, err = timeutil.Parse(stringField(r, "date")); err != nil {
+			return
+		}
+	}
+	setString(r, "timestamp", timeutil.Format(t))
+	delete(r, "date")
+}
+
+// convertFields 原地转换指定的时间字段
+func convertFields(keys ...string) func(rawRecord) {
+	return func(r rawRecord) {
+		for _, key := range keys {
+			s := stringField(r, key)
+			if s == "" || timeutil.IsRFC3339(s) {
+				continue
+			}
+			if t, err := timeutil.Parse(s); err == nil {
+				setString(r, key, timeutil.Format(t))
+			}
+		}
+	}
+}
+
+// convertJSONList 转换 JSON 文件中 listKey 数组的每一项（storage 写回时保留 .bak）
+func convertJSONList(name, listKey string, fn func(rawRecord)) error {
+	if !storage.Exists(name) {
+		return nil
+	}
+	var top rawRecord
+	return storage.Update(name, &top, func() error {
+		return convertList(top, listKey, fn)
+	})
+}
+
+// convertList 转换对象中 listKey 数组的每一项，字段不存在时跳过
+func convertList(top rawRecord, listKey string, fn func(rawRecord)) error {
+	data, ok := top[listKey]
+	if !ok {
+		return nil
+	}
+	var list []rawRecord
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	for _, r := range list {
+		fn(r)
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	top[listKey] = data
+	return nil
+}
+
+// convertJSONObject 转换 JSON 文件的顶层对象
+func convertJSONObject(name string, fn func(rawRecord)) error {
+	if !storage.Exists(name) {
+		return nil
+	}
+	var top rawRecord
+	return storage.Update(name, &top, func() error {
+		if top != nil {
+			fn(top)
+		}
+		return nil
+	})
+}
+
+// convertLog 备份后转换 JSON Lines 日志的每一行
+func convertLog(name string, fn func(rawRecord)) error {
+	if !storage.Exists(name) {
+		return nil
+	}
+	if err := storage.Backup(name, ".v0"); err != nil {
+		return err
+	}
+	return storage.UpdateRecords(name, func(records []rawRecord) ([]rawRecord, error) {
+		for _, r := range records {
+			fn(r)
+		}
+		return records, nil
+	})
+}
+
+// importLegacyNetHistory 把更早版本的 net_history.json 转换后导入分钟日志
+func importLegacyNetHistory() error {
+	const legacyFile = "net_history.json"
+	if !storage.Exists(legacyFile) {
+		return nil
+	}
+
+	var h struct {
+		Records []rawRecord `json:"records"`
+	}
+	if err := storage.Load(legacyFile, &h); err != nil {
+		return err
+	}
+	// 导入后改名前崩溃时会再次导入，按时间戳跳过已导入的记录（旧版记录不区分网卡）
+	existing, err := storage.ReadRecords[rawRecord]("net_minute.jsonl")
+	if err != nil {
+		return err
+	}
+	imported := make(map[string]bool)
+	for _, r := range existing {
+		if _, ok := r["iface"]; !ok {
+			imported[stringField(r, "timestamp")] = true
+		}
+	}
+	var records []rawRecord
+	for _, r := range h.Records {
+		convertNetRecord(r)
+		if !imported[stringField(r, "timestamp")] {
+			records = append(records, r)
+		}
+	}
+	if err := storage.AppendRecords("net_minute.jsonl", records...); err != nil {
+		return err
+	}
+	path := storage.Path(legacyFile)
+	return os.Rename(path, path+".migrated")
+}
+
+func stringField(r rawRecord, key string) string {
+	var s string
+	if data, ok := r[key]; ok {
+		_ = json.Unmarshal(data, &s)
+	}
+	return s
+}
+
+func setString(r rawRecord, key, value string) {
+	data, _ := json.Marshal(value)
+	r[key] = data
+}
@@ -14,14 +14,57 @@ type SystemInfo struct {
 	PublicIP    string  `json:"public_ip"`
 	IPLocation  string  `json:"ip_location"`
 	IPOperator  string  `json:"ip_operator"`
+	PrivacyMode bool    `json:"privacy_mode"` // 隐私模式下不查询公网 IP
+}
+
+// PublicIPInfo 公网 IP 查询结果
+type PublicIPInfo struct {
+	IP        string `json:"ip"`
+	Provider  string `json:"provider"` // 提供结果的服务
+	Location  string `json:"location"` // 来自本地 GeoIP 数据库（或允许时的在线查询）
+	Operator  string `json:"operator"`
+	CheckedAt string `json:"checked_at"`
+	Stale     bool   `json:"stale"`           // 本次查询全部失败，返回的是上次的结果
+	Disabled  bool   `json:"disabled"`        // 隐私模式，未查询
+	Error     string `json:"error,omitempty"` // 各服务的失败原因
+}
+
+// GeoIPInfo IP 归属地及 ASN
+type GeoIPInfo struct {
+	IP          string `json:"ip"`
+	CountryCode string `json:"country_code,omitempty"`
+	Country     string `json:"country,omitempty"`
+	Region      string `json:"region,omitempty"`
+	City        string `json:"city,omitempty"`
+	ASN         uint   `json:"asn,omitempty"`
+	ASOrg       string `json:"as_org,omitempty"`
+	Private     bool   `json:"private,omitempty"` // 内网、回环等非公网地址，不查询
+	Source      string `json:"source,omitempty"`  // offline / online，未查到时为空
+}
+
+// GeoIPDatabase 已加载的 .mmdb 数据库
+type GeoIPDatabase struct {
+	File      string `json:"file"`
+	Type      string `json:"type"` // 如 GeoLite2-City、GeoLite2-ASN、DBIP-City-Lite
+	BuildTime string `json:"build_time"`
+}
+
+// GeoIPStatus GeoIP 数据库状态
+type GeoIPStatus struct {
+	Dir            string          `json:"dir"` // 放置 .mmdb 文件的目录（数据目录）
+	Databases      []GeoIPDatabase `json:"databases"`
+	OnlineFallback bool            `json:"online_fallback"`
 }
 
 // ScanResult 扫描结果
 type ScanResult struct {
-	Category string     `json:"category"`
-	Items    []JunkItem `json:"items"`
-	Size     int64      `json:"size"`
-	Count    int        `json:"count"`
+	Category     string     `json:"category"`      // 分类 ID
+	CategoryName string     `json:"category_name"` // 当前语言的分类名称
+	Items        []JunkItem `json:"items"`
+	Size         int64      `json:"size"`
+	Count        int        `json:"count"`
+	Risk         string     `json:"risk"`        // 风险等级 "low" / "medium" / "high"
+	Description  string     `json:"description"` // 清理影响说明
 }
 
 // JunkItem 垃圾文件条目
@@ -33,9 +76,38 @@ type JunkItem struct {
 
 // CleanResult 清理结果
 type CleanResult struct {
-	FreedSize    int64 `json:"freed_size"`
-	CleanedCount int   `json:"cleaned_count"`
-	FailedCount  int   `json:"failed_count"`
+	FreedSize    int64           `json:"freed_size"`
+	CleanedCount int             `json:"cleaned_count"`
+	FailedCount  int             `json:"failed_count"`
+	Categories   []CategoryFreed `json:"categories"` // 按分类的清理明细
+	Failures     []CleanFailure  `json:"failures"`   // 清理失败的文件
+}
+
+// CleanFailure 清理失败的文件
+type CleanFailure struct {
+	Path     string       `json:"path"`
+	Size     int64        `json:"size"`
+	Category string       `json:"category"`
+	Error    string       `json:"error"`
+	InUse    bool         `json:"in_use"`  // 是否因文件被占用而失败
+	Holders  []FileLocker `json:"holders"` // 占用该文件的进程
+}
+
+// FileLocker 占用文件的进程
+type FileLocker struct {
+	PID      int32  `json:"pid"`
+	Name     string `json:"name"`     // 进程名
+	AppName  string `json:"app_name"` // 应用友好名称（Windows Restart Manager 提供）
+	Service  string `json:"service"`  // 服务短名（若为服务）
+	Username string `json:"username"`
+}
+
+// CategoryFreed 单个分类的清理量
+type CategoryFreed struct {
+	Category     string `json:"category"`                // 分类 ID
+	CategoryName string `json:"category_name,omitempty"` // 当前语言的分类名称（不持久化）
+	FreedSize    int64  `json:"freed_size"`
+	CleanedCount int    `json:"cleaned_count"`
 }
 
 // ProcessInfo 进程信息
@@ -62,7 +134,7 @@ type MemoryOptResult struct {
 type GPUInfo struct {
 	Name       string `json:"name"`
 	Type       string `json:"type"`       // "discrete"(独显) / "integrated"(核显) / "none"(无)
-	TypeLabel  string `json:"type_label"` // 中文标签
+	TypeLabel  string `json:"type_label"` // 当前语言的类型标签
 	VRAM       uint64 `json:"vram"`       // 显存（字节）
 	DriverVer  string `json:"driver_ver"` // 驱动版本
 	Resolution string `json:"resolution"` // 当前分辨率
@@ -75,10 +147,11 @@ type GPUResult struct {
 
 // CleanRecord 单次清理记录
 type CleanRecord struct {
-	Date         string `json:"date"`          // 日期 YYYY-MM-DD
-	Time         string `json:"time"`          // 时间 HH:MM:SS
+	Timestamp    string `json:"timestamp"`     // 清理时间（RFC 3339，带时区偏移）
 	FreedSize    int64  `json:"freed_size"`    // 释放字节数
 	CleanedCount int    `json:"cleaned_count"` // 清理文件数
+
+	Categories []CategoryFreed `json:"categories,omitempty"` // 按分类明细（旧记录无此字段）
 }
 
 // CleanHistory 清理历史
@@ -95,6 +168,30 @@ type CleanHistoryStats struct {
 	LastCleanAgo  string        `json:"last_clean_ago"`  // 距上次清理多久
 	TotalFreed    int64         `json:"total_freed"`     // 累计释放
 	TotalCount    int           `json:"total_count"`     // 累计清理文件数
+
+	CategoryTrends []CategoryTrend `json:"category_trends"` // 按分类的趋势与回涨速度
+}
+
+// CategoryTrend 单个分类的清理趋势
+type CategoryTrend struct {
+	Category       string          `json:"category"`      // 分类 ID
+	CategoryName   string          `json:"category_name"` // 当前语言的分类名称
+	TotalFreed     int64           `json:"total_freed"`
+	TotalCount     int             `json:"total_count"`
+	CleanTimes     int             `json:"clean_times"`      // 清理次数
+	LastCleanTime  string          `json:"last_clean_time"`  // 上次清理时间（RFC 3339）
+	AvgFreed       int64           `json:"avg_freed"`        // 平均每次释放
+	RegrowthPerDay int64           `json:"regrowth_per_day"` // 回涨速度 bytes/天（至少两次清理才有值）
+	RegrowthWeekly int64           `json:"regrowth_weekly"`  // 回涨速度 bytes/周
+	SuggestDays    int             `json:"suggest_days"`     // 建议清理间隔（天），0 表示数据不足
+	Points         []CategoryPoint `json:"points"`           // 每次清理的释放量（用于折线图）
+}
+
+// CategoryPoint 分类单次清理数据点
+type CategoryPoint struct {
+	Time      string `json:"time"` // RFC 3339
+	FreedSize int64  `json:"freed_size"`
+	Count     int    `json:"count"`
 }
 
 // DailyStat 按天统计
@@ -143,10 +240,10 @@ type NetTrafficResult struct {
 
 // NetTrafficRecord 流量采样记录
 type NetTrafficRecord struct {
-	Timestamp string `json:"timestamp"` // YYYY-MM-DD HH:MM
-	Date      string `json:"date"`      // YYYY-MM-DD
-	Sent      uint64 `json:"sent"`      // 该采样周期发送字节
-	Recv      uint64 `json:"recv"`      // 该采样周期接收字节
+	Timestamp string `json:"timestamp"`       // 采样时间（RFC 3339，带时区偏移）；小时/天汇总记录为时段起点
+	Iface     string `json:"iface,omitempty"` // 网卡名称（旧记录为空，表示未区分网卡）
+	Sent      uint64 `json:"sent"`            // 该采样周期发送字节
+	Recv      uint64 `json:"recv"`            // 该采样周期接收字节
 }
 
 // NetTrafficHistory 流量历史
@@ -182,6 +279,38 @@ type NetTrafficStats struct {
 	YearlyStats  []NetYearlyStat  `json:"yearly_stats"`
 	TotalSent    uint64           `json:"total_sent"`
 	TotalRecv    uint64           `json:"total_recv"`
+	Gaps         []NetTrafficGap  `json:"gaps"` // 近 30 天无法统计的时段
+
+	Interfaces []NetInterfaceStats `json:"interfaces"` // 按网卡统计（含已排除的网卡）
+}
+
+// NetInterfaceStats 单个网卡的流量统计
+type NetInterfaceStats struct {
+	Name         string           `json:"name"` // 为空表示旧版未区分网卡的记录
+	Virtual      bool             `json:"virtual"`
+	Included     bool             `json:"included"` // 是否计入总流量
+	DailyStats   []NetDailyStat   `json:"daily_stats"`
+	MonthlyStats []NetMonthlyStat `json:"monthly_stats"`
+	YearlyStats  []NetYearlyStat  `json:"yearly_stats"`
+	TotalSent    uint64           `json:"total_sent"`
+	TotalRecv    uint64           `json:"total_recv"`
+}
+
+// NetInterfaceInfo 网卡信息
+type NetInterfaceInfo struct {
+	Name      string `json:"name"`
+	Virtual   bool   `json:"virtual"`  // 是否识别为虚拟网卡（回环、Hyper-V、VPN 等）
+	Included  bool   `json:"included"` // 是否计入流量统计
+	BytesSent uint64 `json:"bytes_sent"`
+	BytesRecv uint64 `json:"bytes_recv"`
+}
+
+// NetTrafficGap 流量统计缺口（重启、计数器重置等导致该时段流量未知）
+type NetTrafficGap struct {
+	Start  string `json:"start"` // RFC 3339
+	End    string `json:"end"`
+	Iface  string `json:"iface,omitempty"` // 出现缺口的网卡，为空表示全部网卡
+	Reason string `json:"reason"`          // "reboot" / "counter_reset" / "implausible"
 }
 
 // DiskInfo 磁盘分区信息
@@ -210,8 +339,7 @@ type DiskScanResult struct {
 
 // MemOptRecord 单次内存优化记录
 type MemOptRecord struct {
-	Date          string  `json:"date"`
-	Time          string  `json:"time"`
+	Timestamp     string  `json:"timestamp"` // RFC 3339，带时区偏移
 	FreedMB       float64 `json:"freed_mb"`
 	BeforePercent float64 `json:"before_percent"`
 	AfterPercent  float64 `json:"after_percent"`
@@ -256,18 +384,470 @@ type MemOptStats struct {
 	TotalCount    int                 `json:"total_count"`
 }
 
-// PortInfo 端口信息
+// PortInfo 端口信息（一条 TCP 连接或 UDP 套接字）
 type PortInfo struct {
-	ListenAddr  string `json:"listen_addr"`  // 监听地址，如 "0.0.0.0:8080"
-	Port        uint16 `json:"port"`         // 端口号
+	ListenAddr  string `json:"listen_addr"`  // 本地地址，如 "0.0.0.0:8080" / "[::]:8080"
+	LocalIP     string `json:"local_ip"`     // 本地 IP
+	Port        uint16 `json:"port"`         // 本地端口号
+	RemoteAddr  string `json:"remote_addr"`  // 远端地址，监听中或未连接的 UDP 为空
+	RemoteIP    string `json:"remote_ip"`    // 远端 IP
+	RemotePort  uint16 `json:"remote_port"`  // 远端端口
 	Proto       string `json:"proto"`        // 协议类型 "tcp" / "udp"
+	Family      string `json:"family"`       // 地址族 "ipv4" / "ipv6"
 	PID         int32  `json:"pid"`          // 进程ID
 	ProcessName string `json:"process_name"` // 进程名
-	Status      string `json:"status"`       // 连接状态
+	Status      string `json:"status"`       // TCP 连接状态（LISTEN / ESTABLISHED / TIME_WAIT 等），UDP 为空
+	Listening   bool   `json:"listening"`    // 是否在监听（TCP LISTEN 或未连接的 UDP 套接字）
+}
+
+// PortFilter 端口列表过滤条件，零值字段不参与过滤
+type PortFilter struct {
+	Proto         string   `json:"proto"`          // "tcp" / "udp"
+	Family        string   `json:"family"`         // "ipv4" / "ipv6"
+	States        []string `json:"states"`         // TCP 状态，匹配任意一个即可
+	PID           int32    `json:"pid"`            // 所属进程
+	MinPort       uint16   `json:"min_port"`       // 本地端口下限（含）
+	MaxPort       uint16   `json:"max_port"`       // 本地端口上限（含）
+	ListeningOnly bool     `json:"listening_only"` // 只看监听中的端口
 }
 
 // PortScanResult 端口扫描结果
 type PortScanResult struct {
-	Ports []PortInfo `json:"ports"`
-	Count int        `json:"count"`
+	Ports       []PortInfo     `json:"ports"`
+	Count       int            `json:"count"`
+	TCPCount    int            `json:"tcp_count"`
+	UDPCount    int            `json:"udp_count"`
+	ListenCount int            `json:"listen_count"` // 监听中的端口数
+	StateCounts map[string]int `json:"state_counts"` // 按 TCP 状态统计
+	ScanTime    string         `json:"scan_time"`    // 扫描时间（RFC 3339）
+}
+
+// KillPortOptions 按端口结束进程的选项
+type KillPortOptions struct {
+	ListeningOnly     bool    `json:"listening_only"`      // 只结束监听该端口的进程
+	Proto             string  `json:"proto"`               // 只匹配 "tcp" / "udp"，为空不限
+	GracefulTimeoutMs int     `json:"graceful_timeout_ms"` // 正常结束的等待时间（毫秒），超时后强制结束，默认 3000
+	Force             bool    `json:"force"`               // 跳过正常结束，直接强制结束
+	Tree              bool    `json:"tree"`                // 连同子进程一起结束
+	Override          bool    `json:"override"`            // 用户已确认，允许结束受保护的关键进程
+	PIDs              []int32 `json:"pids,omitempty"`      // 只结束这些进程（须占用该端口），为空时结束全部
+}
+
+// ProcessKillResult 单个进程的结束结果
+type ProcessKillResult struct {
+	PID         int32   `json:"pid"`
+	Name        string  `json:"name"`
+	Status      string  `json:"status"`             // killed / already_exited / access_denied / protected / failed
+	Method      string  `json:"method,omitempty"`   // graceful / force
+	Children    []int32 `json:"children,omitempty"` // 一并结束的子进程（Tree 选项）
+	Error       string  `json:"error,omitempty"`
+	Overridable bool    `json:"overridable,omitempty"` // 受保护但用户确认后可以强行结束（Override 重试）
+}
+
+// ProcessProtection 进程保护设置（进程名，不区分大小写）
+type ProcessProtection struct {
+	Critical []string `json:"critical,omitempty"` // 内置关键进程（只读，不保存）
+	Allow    []string `json:"allow"`              // 允许结束：免于禁止结束列表的保护，不能解除内置关键进程的保护
+	Block    []string `json:"block"`              // 禁止结束：额外保护的进程
+}
+
+// AppError 返回给前端的结构化错误
+type AppError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// KillPortResult 按端口结束进程的结果
+type KillPortResult struct {
+	Port    uint16              `json:"port"`
+	Killed  int                 `json:"killed"` // 成功结束的进程数
+	Results []ProcessKillResult `json:"results"`
+}
+
+// WatchedPort 关注的端口
+type WatchedPort struct {
+	Port  uint16 `json:"port"`
+	Label string `json:"label"` // 备注，如 "前端 dev server"
+}
+
+// PortHolder 占用端口的进程
+type PortHolder struct {
+	PID        int32  `json:"pid"`
+	Name       string `json:"name"`
+	Cmdline    string `json:"cmdline"` // 命令行（无权限读取时为空）
+	Proto      string `json:"proto"`
+	ListenAddr string `json:"listen_addr"`
+}
+
+// PortHolderRecord 端口占用记录
+type PortHolderRecord struct {
+	PortHolder
+	Port  uint16 `json:"port"`
+	Start string `json:"start"` // 首次发现占用的时间（RFC 3339）
+	End   string `json:"end"`   // 发现释放的时间，仍在占用时为空
+}
+
+// PortWatchConfig 端口关注列表及占用历史（port_watch.json）
+type PortWatchConfig struct {
+	Ports   []WatchedPort      `json:"ports"`
+	History []PortHolderRecord `json:"history"`
+}
+
+// PortWatchStatus 关注端口的当前状态
+type PortWatchStatus struct {
+	WatchedPort
+	Occupied bool               `json:"occupied"`
+	Holders  []PortHolder       `json:"holders"`
+	History  []PortHolderRecord `json:"history"` // 最近的在前
+}
+
+// PortWatchEvent 关注端口的占用变化（通过 Wails 事件 "port:watch" 推送）
+type PortWatchEvent struct {
+	WatchedPort
+	Event   string       `json:"event"`   // occupied / freed / changed
+	Holders []PortHolder `json:"holders"` // freed 时为释放前的占用进程
+	Time    string       `json:"time"`
+}
+
+// ServiceFingerprint 本机端口的服务识别结果
+type ServiceFingerprint struct {
+	Port        uint16 `json:"port"`
+	Address     string `json:"address"` // 实际连接的回环地址
+	Service     string `json:"service"` // http / https / tls / redis / postgresql / mysql / ssh / unknown
+	Version     string `json:"version,omitempty"`
+	Banner      string `json:"banner,omitempty"` // 未识别时服务端发送的欢迎信息
+	HTTPStatus  int    `json:"http_status,omitempty"`
+	Server      string `json:"server,omitempty"` // HTTP Server 头
+	Title       string `json:"title,omitempty"`  // 页面标题
+	TLSSubject  string `json:"tls_subject,omitempty"`
+	TLSIssuer   string `json:"tls_issuer,omitempty"`
+	TLSNotAfter string `json:"tls_not_after,omitempty"`
+	TLSExpired  bool   `json:"tls_expired,omitempty"`
+	Error       string `json:"error,omitempty"` // 回环地址无法连接时的错误
+}
+
+// PortForwardRule TCP 端口转发规则（持久化的保存在 port_forwards.json）
+type PortForwardRule struct {
+	ID         string `json:"id"`
+	ListenAddr string `json:"listen_addr"` // 如 0.0.0.0:8080
+	TargetAddr string `json:"target_addr"` // 如 127.0.0.1:3000
+	Persist    bool   `json:"persist"`     // 下次启动时自动恢复
+	Created    string `json:"created"`
+}
+
+// PortForwardStatus 端口转发的运行状态
+type PortForwardStatus struct {
+	PortForwardRule
+	Running  bool   `json:"running"`
+	Error    string `json:"error,omitempty"` // 恢复时启动失败的原因
+	Active   int    `json:"active"`          // 当前连接数
+	Total    int    `json:"total"`           // 累计连接数
+	BytesIn  uint64 `json:"bytes_in"`        // 客户端发往目标的字节数
+	BytesOut uint64 `json:"bytes_out"`       // 目标返回客户端的字节数
+}
+
+// ConnRecord 某进程访问某远端端点的历史（按采样合并）
+type ConnRecord struct {
+	Process    string `json:"process"`
+	Proto      string `json:"proto"`
+	RemoteIP   string `json:"remote_ip"`
+	RemotePort uint16 `json:"remote_port"`          // 入站连接为 0
+	LocalPort  uint16 `json:"local_port,omitempty"` // 入站连接的本机监听端口
+	Inbound    bool   `json:"inbound"`              // 远端连入本机监听端口
+	FirstSeen  string `json:"first_seen"`
+	LastSeen   string `json:"last_seen"`
+	Count      int    `json:"count"` // 被采样到的次数
+}
+
+// ConnHistory 连接历史（conn_history.json）
+type ConnHistory struct {
+	Records []ConnRecord `json:"records"`
+}
+
+// ConnHistoryQuery 连接历史查询条件，零值字段不参与过滤
+type ConnHistoryQuery struct {
+	GroupBy      string `json:"group_by"`  // process（默认）/ remote
+	Process      string `json:"process"`   // 进程名包含（不区分大小写）
+	RemoteIP     string `json:"remote_ip"` // 远端 IP 前缀
+	RemotePort   uint16 `json:"remote_port"`
+	Days         int    `json:"days"` // 只看最近 N 天出现过的
+	OutboundOnly bool   `json:"outbound_only"`
+}
+
+// ConnHistoryGroup 按进程或远端主机分组的连接历史
+type ConnHistoryGroup struct {
+	Key       string       `json:"key"`     // 进程名或远端 IP
+	Members   int          `json:"members"` // 按进程分组时为远端主机数，按远端分组时为进程数
+	Count     int          `json:"count"`
+	FirstSeen string       `json:"first_seen"`
+	LastSeen  string       `json:"last_seen"`
+	Records   []ConnRecord `json:"records"`       // 按出现次数从多到少
+	Geo       *GeoIPInfo   `json:"geo,omitempty"` // 按远端主机分组时的归属地
+}
+
+// ConnHistoryResult 连接历史查询结果
+type ConnHistoryResult struct {
+	GroupBy string             `json:"group_by"`
+	Total   int                `json:"total"` // 匹配的记录数
+	Groups  []ConnHistoryGroup `json:"groups"`
+}
+
+// PortRange 端口范围（含两端）
+type PortRange struct {
+	Start uint16 `json:"start"`
+	End   uint16 `json:"end"`
+	Proto string `json:"proto"`
+}
+
+// FreePortRequest 查找空闲端口的条件
+type FreePortRequest struct {
+	Start      uint16   `json:"start"`
+	End        uint16   `json:"end"`
+	Proto      string   `json:"proto"`      // tcp / udp / both，为空时为 tcp
+	Interfaces []string `json:"interfaces"` // 网卡名或 IP 地址，为空时为 0.0.0.0
+	Count      int      `json:"count"`      // 需要的端口数，默认 1，最多 100
+	Hold       bool     `json:"hold"`       // 找到后保持监听占住端口，直到释放
+}
+
+// FreePortResult 查找空闲端口的结果
+type FreePortResult struct {
+	Ports     []uint16    `json:"ports"`
+	Addresses []string    `json:"addresses"` // 实际探测的地址
+	Excluded  []PortRange `json:"excluded"`  // 与查找范围重叠的系统保留/排除范围
+	Reserved  int         `json:"reserved"`  // 因保留/排除跳过的端口数
+	InUse     int         `json:"in_use"`    // 因已被占用跳过的端口数
+	Held      bool        `json:"held"`
+}
+
+// HeldPort 本程序为调用方占住的端口
+type HeldPort struct {
+	Port      uint16   `json:"port"`
+	Proto     string   `json:"proto"`
+	Addresses []string `json:"addresses"`
+	Since     string   `json:"since"`
+}
+
+// DownloadFileInfo 下载目录中的文件
+type DownloadFileInfo struct {
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	Ext        string `json:"ext"`
+	FileType   string `json:"file_type"`   // "installer" / "archive" / "disk_image" / "document" / "media" / "other"
+	ModTime    string `json:"mod_time"`    // 下载（修改）时间 YYYY-MM-DD HH:MM:SS
+	AgeDays    int    `json:"age_days"`    // 距今天数
+	AgeBucket  string `json:"age_bucket"`  // "week" / "month" / "quarter" / "older"
+	MatchedApp string `json:"matched_app"` // 匹配到的已安装程序名（为空表示未匹配）
+	Suggest    bool   `json:"suggest"`     // 是否建议隔离
+	Reason     string `json:"reason"`      // 建议原因
+}
+
+// DownloadGroup 按类型和时间段分组统计
+type DownloadGroup struct {
+	FileType  string `json:"file_type"`
+	AgeBucket string `json:"age_bucket"`
+	Count     int    `json:"count"`
+	Size      int64  `json:"size"`
+}
+
+// DownloadsReport 下载目录分析结果
+type DownloadsReport struct {
+	Dir           string             `json:"dir"`
+	Files         []DownloadFileInfo `json:"files"`
+	Groups        []DownloadGroup    `json:"groups"`
+	Suggestions   []DownloadFileInfo `json:"suggestions"` // 建议隔离的文件
+	TotalSize     int64              `json:"total_size"`
+	TotalCount    int                `json:"total_count"`
+	SuggestSize   int64              `json:"suggest_size"`   // 建议隔离的总大小
+	InstalledApps int                `json:"installed_apps"` // 参与匹配的已安装程序数
+}
+
+// ScanSnapshot 单次扫描的精简摘要（用于前后对比）
+type ScanSnapshot struct {
+	ID         string             `json:"id"`
+	Kind       string             `json:"kind"` // "junk"(垃圾扫描) / "large"(大文件扫描)
+	Time       string             `json:"time"` // RFC 3339
+	Root       string             `json:"root,omitempty"`
+	MinSizeMB  int64              `json:"min_size_mb,omitempty"`
+	TotalSize  int64              `json:"total_size"`
+	TotalCount int                `json:"total_count"`
+	Categories []CategorySnapshot `json:"categories,omitempty"`
+	Files      []LargeFileInfo    `json:"files,omitempty"`
+}
+
+// CategorySnapshot 分类摘要
+type CategorySnapshot struct {
+	Category string    `json:"category"` // 分类 ID
+	Size     int64     `json:"size"`
+	Count    int       `json:"count"`
+	TopDirs  []DirSize `json:"top_dirs"`
+	Dirs     []string  `json:"dirs,omitempty"` // 全部一级子目录，用来区分"已消失"和"跌出前 N 名"（旧摘要没有）
+}
+
+// DirSize 目录占用
+type DirSize struct {
+	Path     string `json:"path"`
+	Category string `json:"category,omitempty"`
+	Size     int64  `json:"size"`
+	Count    int    `json:"count"`
+}
+
+// ScanSnapshotHistory 扫描摘要历史
+type ScanSnapshotHistory struct {
+	Snapshots []ScanSnapshot `json:"snapshots"`
+}
+
+// CategoryDelta 分类变化量
+type CategoryDelta struct {
+	Category     string `json:"category"`      // 分类 ID
+	CategoryName string `json:"category_name"` // 当前语言的分类名称
+	OldSize      int64  `json:"old_size"`
+	NewSize      int64  `json:"new_size"`
+	SizeDelta    int64  `json:"size_delta"`
+	OldCount     int    `json:"old_count"`
+	NewCount     int    `json:"new_count"`
+	CountDelta   int    `json:"count_delta"`
+}
+
+// ScanDiff 两次扫描的对比结果
+type ScanDiff struct {
+	OldID          string          `json:"old_id"`
+	OldTime        string          `json:"old_time"`
+	NewID          string          `json:"new_id"`
+	NewTime        string          `json:"new_time"`
+	Kind           string          `json:"kind"`
+	SizeDelta      int64           `json:"size_delta"`
+	CategoryDeltas []CategoryDelta `json:"category_deltas"`
+	AddedDirs      []DirSize       `json:"added_dirs"`
+	RemovedDirs    []DirSize       `json:"removed_dirs"`
+	AddedFiles     []LargeFileInfo `json:"added_files"`
+	RemovedFiles   []LargeFileInfo `json:"removed_files"`
+}
+
+// RecommendItem 单个分类的清理建议
+type RecommendItem struct {
+	Category     string `json:"category"`      // 分类 ID
+	CategoryName string `json:"category_name"` // 当前语言的分类名称
+	Risk         string `json:"risk"`
+	Size         int64  `json:"size"`
+	Recommended  bool   `json:"recommended"`
+	Reason       string `json:"reason"`
+}
+
+// CleanRecommendation 清理推荐结果
+type CleanRecommendation struct {
+	Items           []RecommendItem `json:"items"`
+	Recommended     []string        `json:"recommended"`      // 推荐选中的分类 ID
+	RecommendedSize int64           `json:"recommended_size"` // 推荐清理的总大小
+	DiskFree        uint64          `json:"disk_free"`        // 系统盘剩余空间
+	DiskTotal       uint64          `json:"disk_total"`
+	LowDisk         bool            `json:"low_disk"` // 系统盘空间不足
+}
+
+// NetBudget 网卡流量套餐（按计费周期统计）
+type NetBudget struct {
+	Iface         string  `json:"iface"`           // 网卡名称，为空表示所有计入统计的网卡
+	BudgetBytes   uint64  `json:"budget_bytes"`    // 每个周期的流量额度
+	CycleStartDay int     `json:"cycle_start_day"` // 计费周期起始日（1-31，超过当月天数按月末计算）
+	SentWeight    float64 `json:"sent_weight"`     // 上传流量计费权重（默认 1）
+	RecvWeight    float64 `json:"recv_weight"`     // 下载流量计费权重（默认 1）
+}
+
+// NetBudgetStatus 当前周期的套餐使用情况
+type NetBudgetStatus struct {
+	Budget           NetBudget `json:"budget"`
+	CycleStart       string    `json:"cycle_start"` // YYYY-MM-DD
+	CycleEnd         string    `json:"cycle_end"`   // 下个周期开始日 YYYY-MM-DD
+	DaysLeft         int       `json:"days_left"`
+	UsedBytes        uint64    `json:"used_bytes"` // 按权重计算的已用流量
+	UsedPercent      float64   `json:"used_percent"`
+	DailyRate        uint64    `json:"daily_rate"`      // 近 7 天日均用量
+	ProjectedBytes   uint64    `json:"projected_bytes"` // 按近期速率预测的周期末用量
+	ProjectedPercent float64   `json:"projected_percent"`
+	Level            int       `json:"level"` // 已达到的提醒档位 0 / 50 / 80 / 100
+}
+
+// NetBudgetAlert 流量套餐提醒
+type NetBudgetAlert struct {
+	Iface       string  `json:"iface"`
+	Threshold   int     `json:"threshold"` // 50 / 80 / 100
+	UsedBytes   uint64  `json:"used_bytes"`
+	BudgetBytes uint64  `json:"budget_bytes"`
+	UsedPercent float64 `json:"used_percent"`
+	CycleStart  string  `json:"cycle_start"`
+	CycleEnd    string  `json:"cycle_end"`
+}
+
+// Settings 应用设置
+type Settings struct {
+	NetSampleInterval    int `json:"net_sample_interval"`     // 流量采样间隔（秒）
+	PublicIPCacheMinutes int `json:"public_ip_cache_minutes"` // 公网 IP 信息缓存时间（分钟）
+	MemHistoryDays       int `json:"mem_history_days"`        // 内存优化历史保留天数
+	ListeningPortMin     int `json:"listening_port_min"`      // 监听端口列表默认起始端口
+	LargeFileLimit       int `json:"large_file_limit"`        // 大文件扫描返回数量
+	CPUSampleMillis      int `json:"cpu_sample_ms"`           // 进程 CPU 占用采样时长（毫秒）
+	PortWatchInterval    int `json:"port_watch_interval"`     // 关注端口轮询间隔（秒）
+	ConnSampleInterval   int `json:"conn_sample_interval"`    // 连接历史采样间隔（秒）
+	ConnHistoryDays      int `json:"conn_history_days"`       // 连接历史保留天数
+	NetMinuteDays        int `json:"net_minute_days"`         // 分钟级流量记录保留天数（之后汇总为小时）
+	NetHourlyDays        int `json:"net_hourly_days"`         // 小时级流量记录保留天数（之后汇总为天）
+
+	Locale            string `json:"locale"`               // 界面语言，如 "zh-CN" / "en-US"
+	GeoIPOnline       bool   `json:"geoip_online"`         // 允许在线查询用户点选的 IP 和本机公网 IP（ip-api.com，明文 HTTP，不批量查询）
+	PublicIPProviders string `json:"public_ip_providers"`  // 依次尝试的公网 IP 服务，逗号分隔：ipify、ifconfig.co、custom
+	PublicIPCustomURL string `json:"public_ip_custom_url"` // 自建公网 IP 服务地址（HTTPS）
+	PrivacyMode       bool   `json:"privacy_mode"`         // 隐私模式：不发出任何外网请求（公网 IP、在线归属地、检查更新）
+}
+
+// DataDirInfo 数据目录信息
+type DataDirInfo struct {
+	Path   string `json:"path"`
+	Source string `json:"source"` // flag / env / portable / home / fallback
+	// 启动时数据格式升级失败的原因（为空表示正常）；失败时暂停写入清理/内存优化历史
+	MigrationError string `json:"migration_error,omitempty"`
+}
+
+// NetTrafficTiers 三级流量记录（导出/导入用）
+type NetTrafficTiers struct {
+	Minute []NetTrafficRecord `json:"minute"`
+	Hourly []NetTrafficRecord `json:"hourly"`
+	Daily  []NetTrafficRecord `json:"daily"`
+}
+
+// NetConfig 网卡统计设置与流量套餐（导出/导入用）
+type NetConfig struct {
+	Include []string    `json:"include"`
+	Exclude []string    `json:"exclude"`
+	Budgets []NetBudget `json:"budgets"`
+}
+
+// BackupManifest 导出归档的清单
+type BackupManifest struct {
+	Format        string        `json:"format"`         // 固定为 wincleaner-backup
+	Version       int           `json:"version"`        // 归档格式版本
+	SchemaVersion int           `json:"schema_version"` // 导出时的数据格式版本
+	AppVersion    string        `json:"app_version"`
+	CreatedAt     string        `json:"created_at"` // RFC 3339
+	Entries       []BackupEntry `json:"entries"`
+}
+
+// BackupEntry 归档中的一个文件
+type BackupEntry struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	SHA256  string `json:"sha256"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Mode    string             `json:"mode"` // merge / replace
+	Entries []ImportEntryStats `json:"entries"`
+}
+
+// ImportEntryStats 单个文件的导入统计
+type ImportEntryStats struct {
+	Name    string `json:"name"`
+	Added   int    `json:"added"`
+	Skipped int    `json:"skipped"` // 重复而跳过的记录
 }
@@ -0,0 +1,266 @@
+package monitor
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/internal/settings"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+)
+
+const (
+	connHistoryFile = "conn_history.json"
+	// maxConnRecords 记录条数上限，超出时丢弃最久未出现的
+	maxConnRecords = 20000
+	// connFlushInterval 内存中的采样写入文件的间隔
+	connFlushInterval = 10 * time.Minute
+
+	ConnGroupProcess = "process"
+	ConnGroupRemote  = "remote"
+)
+
+// connKey 同一进程、协议、方向访问同一远端端点的连接合并为一条记录
+type connKey struct {
+	process    string
+	proto      string
+	remoteIP   string
+	remotePort uint16
+	localPort  uint16
+	inbound    bool
+}
+
+func recordKey(r model.ConnRecord) connKey {
+	return connKey{r.Process, r.Proto, r.RemoteIP, r.RemotePort, r.LocalPort, r.Inbound}
+}
+
+// 尚未写入文件的采样：每个端点本段时间内的首次/最近出现时间和出现次数
+var (
+	connMu        sync.Mutex
+	connPending   = make(map[connKey]*model.ConnRecord)
+	lastConnFlush = time.Now()
+)
+
+// RecordConnSample 采样一次连接表，更新各远端端点的首次/最近出现时间和出现次数
+//
+// 只记录有远端地址且不是回环地址的连接。远端连入本机监听端口的记为入站，
+// 入站连接的远端端口是临时端口，改为记录本机端口，避免每个连接各占一条记录。
+// 采样结果先合并在内存中，每 connFlushInterval 写一次文件，没有新采样时不写。
+func RecordConnSample() error {
+	conns, err := ListConnections(model.PortFilter{})
+	if err != nil {
+		return err
+	}
+
+	listening := map[string]map[uint16]bool{"tcp": {}, "udp": {}}
+	for _, c := range conns.Ports {
+		if c.Listening {
+			listening[c.Proto][c.Port] = true
+		}
+	}
+	observed := make(map[connKey]bool)
+	for _, c := range conns.Ports {
+		if c.Listening || c.RemoteIP == "" || c.PID == 0 {
+			continue
+		}
+		ip := net.ParseIP(c.RemoteIP)
+		if ip == nil || ip.IsLoopback() || ip.IsUnspecified() {
+			continue
+		}
+		k := connKey{process: c.ProcessName, proto: c.Proto, remoteIP: ip.String()}
+		if listening[c.Proto][c.Port] {
+			k.inbound = true
+			k.localPort = c.Port
+		} else {
+			k.remotePort = c.RemotePort
+		}
+		observed[k] = true
+	}
+
+	stamp := timeutil.Now()
+	connMu.Lock()
+	defer connMu.Unlock()
+	for k := range observed {
+		if r, ok := connPending[k]; ok {
+			r.LastSeen = stamp
+			r.Count++
+			continue
+		}
+		connPending[k] = &model.ConnRecord{
+			Process:    k.process,
+			Proto:      k.proto,
+			RemoteIP:   k.remoteIP,
+			RemotePort: k.remotePort,
+			LocalPort:  k.localPort,
+			Inbound:    k.inbound,
+			FirstSeen:  stamp,
+			LastSeen:   stamp,
+			Count:      1,
+		}
+	}
+	if time.Since(lastConnFlush) < connFlushInterval {
+		return nil
+	}
+	return flushConnLocked()
+}
+
+// FlushConnHistory 把内存中的采样写入文件（程序退出、查询前调用）
+func FlushConnHistory() error {
+	connMu.Lock()
+	defer connMu.Unlock()
+	return flushConnLocked()
+}
+
+// flushConnLocked 合并待写入的采样并清理过期记录，没有新采样时不写文件；调用方持有 connMu
+func flushConnLocked() error {
+	lastConnFlush = time.Now()
+	if len(connPending) == 0 {
+		return nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -settings.Get().ConnHistoryDays)
+	var history model.ConnHistory
+	err := storage.Update(connHistoryFile, &history, func() error {
+		index := make(map[connKey]int, len(history.Records))
+		for i, r := range history.Records {
+			index[recordKey(r)] = i
+		}
+		for k, p := range connPending {
+			if i, ok := index[k]; ok {
+				history.Records[i].LastSeen = p.LastSeen
+				history.Records[i].Count += p.Count
+				continue
+			}
+			history.Records = append(history.Records, *p)
+		}
+		history.Records = pruneConnRecords(history.Records, cutoff)
+		return nil
+	})
+	if err == nil {
+		connPending = make(map[connKey]*model.ConnRecord)
+	}
+	return err
+}
+
+// pruneConnRecords 删除超过保留天数未出现的记录，并限制总条数
+func pruneConnRecords(records []model.ConnRecord, cutoff time.Time) []model.ConnRecord {
+	kept := records[:0]
+	for _, r := range records {
+		if at, err := timeutil.Parse(r.LastSeen); err != nil || !at.Before(cutoff) {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) > maxConnRecords {
+		sort.SliceStable(kept, func(i, j int) bool { return timeutil.Before(kept[j].LastSeen, kept[i].LastSeen) })
+		kept = kept[:maxConnRecords]
+	}
+	return kept
+}
+
+// QueryConnHistory 按进程或远端主机分组查询连接历史
+func QueryConnHistory(q model.ConnHistoryQuery) (*model.ConnHistoryResult, error) {
+	groupBy := q.GroupBy
+	if groupBy == "" {
+		groupBy = ConnGroupProcess
+	}
+	if groupBy != ConnGroupProcess && groupBy != ConnGroupRemote {
+		return nil, i18n.Errorf("connhistory.invalid_group", q.GroupBy)
+	}
+
+	if err := FlushConnHistory(); err != nil {
+		return nil, err
+	}
+	var history model.ConnHistory
+	if err := storage.Load(connHistoryFile, &history); err != nil {
+		return nil, err
+	}
+	var since time.Time
+	if q.Days > 0 {
+		since = time.Now().AddDate(0, 0, -q.Days)
+	}
+
+	groups := make(map[string]*model.ConnHistoryGroup)
+	members := make(map[string]map[string]bool) // 每组涉及的远端主机或进程
+	result := &model.ConnHistoryResult{GroupBy: groupBy, Groups: []model.ConnHistoryGroup{}}
+	for _, r := range history.Records {
+		if !matchConnQuery(r, q, since) {
+			continue
+		}
+		key, member := r.Process, r.RemoteIP
+		if groupBy == ConnGroupRemote {
+			key, member = r.RemoteIP, r.Process
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &model.ConnHistoryGroup{Key: key, FirstSeen: r.FirstSeen, LastSeen: r.LastSeen}
+			groups[key] = g
+			members[key] = make(map[string]bool)
+		}
+		g.Records = append(g.Records, r)
+		g.Count += r.Count
+		members[key][member] = true
+		if timeutil.Before(r.FirstSeen, g.FirstSeen) {
+			g.FirstSeen = r.FirstSeen
+		}
+		if timeutil.Before(g.LastSeen, r.LastSeen) {
+			g.LastSeen = r.LastSeen
+		}
+		result.Total++
+	}
+
+	for key, g := range groups {
+		g.Members = len(members[key])
+		sort.Slice(g.Records, func(i, j int) bool { return g.Records[i].Count > g.Records[j].Count })
+		result.Groups = append(result.Groups, *g)
+	}
+	// 按远端主机分组时附上归属地和 ASN
+	if groupBy == ConnGroupRemote && len(result.Groups) > 0 {
+		ips := make([]string, len(result.Groups))
+		for i, g := range result.Groups {
+			ips[i] = g.Key
+		}
+		for i, info := range LookupGeoIP(ips) {
+			if info.Source != "" {
+				result.Groups[i].Geo = &info
+			}
+		}
+	}
+	// 最近出现的在前
+	sort.Slice(result.Groups, func(i, j int) bool {
+		return timeutil.Before(result.Groups[j].LastSeen, result.Groups[i].LastSeen)
+	})
+	return result, nil
+}
+
+func matchConnQuery(r model.ConnRecord, q model.ConnHistoryQuery, since time.Time) bool {
+	if q.OutboundOnly && r.Inbound {
+		return false
+	}
+	if q.Process != "" && !strings.Contains(strings.ToLower(r.Process), strings.ToLower(q.Process)) {
+		return false
+	}
+	if q.RemoteIP != "" && !strings.HasPrefix(r.RemoteIP, q.RemoteIP) {
+		return false
+	}
+	if q.RemotePort != 0 && r.RemotePort != q.RemotePort {
+		return false
+	}
+	if !since.IsZero() {
+		if at, err := timeutil.Parse(r.LastSeen); err == nil && at.Before(since) {
+			return false
+		}
+	}
+	return true
+}
+
+// ClearConnHistory 清空连接历史（包括尚未写入的采样）
+func ClearConnHistory() error {
+	connMu.Lock()
+	defer connMu.Unlock()
+	connPending = make(map[connKey]*model.ConnRecord)
+	return storage.Save(connHistoryFile, model.ConnHistory{})
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"win-cleaner/internal/model"
+	"win-cleaner/internal/settings"
 	"win-cleaner/pkg/winapi"
 
 	"github.com/shirou/gopsutil/v3/disk"
@@ -44,7 +45,7 @@ func ScanLargeFiles(root string, minSizeMB int64, topN int) []model.LargeFileInf
 		minSizeMB = 50
 	}
 	if topN <= 0 {
-		topN = 100
+		topN = settings.Get().LargeFileLimit
 	}
 
 	minBytes := minSizeMB * 1024 * 1024
@@ -0,0 +1,40 @@
+package monitor
+
+import (
+	"path/filepath"
+	"strings"
+
+	"win-cleaner/internal/model"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// FindFileLockers 查询占用指定文件的进程（PID、进程名、用户），可配合 KillProcess 结束占用进程
+func FindFileLockers(path string) ([]model.FileLocker, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lockers, err := findFileLockers(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range lockers {
+		p, err := process.NewProcess(lockers[i].PID)
+		if err != nil {
+			continue // 进程可能已退出
+		}
+		if name, err := p.Name(); err == nil && name != "" {
+			lockers[i].Name = name
+		}
+		username, _ := p.Username()
+		// 简化用户名：去掉域前缀
+		if idx := strings.LastIndex(username, "\\"); idx >= 0 {
+			username = username[idx+1:]
+		}
+		lockers[i].Username = username
+	}
+	return lockers, nil
+}
@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"win-cleaner/internal/model"
+)
+
+// findFileLockers 扫描 /proc/*/fd，找出打开了该文件的进程（无权限的进程会被跳过）
+func findFileLockers(path string) ([]model.FileLocker, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	var lockers []model.FileLocker
+	for _, e := range entries {
+		pid, err := strconv.ParseInt(e.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", e.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err == nil && target == path {
+				lockers = append(lockers, model.FileLocker{PID: int32(pid)})
+				break
+			}
+		}
+	}
+	return lockers, nil
+}
@@ -0,0 +1,25 @@
+package monitor
+
+import (
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/winapi"
+)
+
+// findFileLockers 通过 Restart Manager 查询占用文件的进程
+func findFileLockers(path string) ([]model.FileLocker, error) {
+	procs, err := winapi.GetFileLockers(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lockers := make([]model.FileLocker, 0, len(procs))
+	for _, p := range procs {
+		lockers = append(lockers, model.FileLocker{
+			PID:     int32(p.PID),
+			Name:    p.AppName,
+			AppName: p.AppName,
+			Service: p.ServiceName,
+		})
+	}
+	return lockers, nil
+}
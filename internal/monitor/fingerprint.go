@@ -0,0 +1,252 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+)
+
+// 识别出的服务类型
+const (
+	ServiceHTTP     = "http"
+	ServiceHTTPS    = "https"
+	ServiceTLS      = "tls" // TLS 握手成功但不是 HTTP
+	ServiceRedis    = "redis"
+	ServicePostgres = "postgresql"
+	ServiceMySQL    = "mysql"
+	ServiceSSH      = "ssh"
+	ServiceUnknown  = "unknown"
+)
+
+const (
+	fingerprintDialTimeout   = 500 * time.Millisecond
+	fingerprintBannerTimeout = 500 * time.Millisecond // 等待服务端主动发送欢迎信息
+	fingerprintReadTimeout   = 800 * time.Millisecond
+	fingerprintWorkers       = 16
+	maxFingerprintPorts      = 500
+	maxTitleBody             = 64 << 10
+)
+
+var titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// FingerprintPorts 识别本机端口上运行的服务
+//
+// 只连接回环地址（127.0.0.1 / ::1），不会向外发送任何数据；
+// 仅绑定在其他网卡地址上的端口无法识别。
+func FingerprintPorts(ports []uint16) []model.ServiceFingerprint {
+	seen := make(map[uint16]bool)
+	var unique []uint16
+	for _, p := range ports {
+		if p != 0 && !seen[p] && len(unique) < maxFingerprintPorts {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+
+	results := make([]model.ServiceFingerprint, len(unique))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < fingerprintWorkers && w < len(unique); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = FingerprintPort(unique[i])
+			}
+		}()
+	}
+	for i := range unique {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// FingerprintPort 识别单个端口：先读取欢迎信息（SSH/MySQL），再依次尝试 TLS、HTTP、Redis、PostgreSQL
+//
+// 先试 TLS：不少 HTTPS 服务收到明文请求也会回复 400，先试 HTTP 会误判。
+func FingerprintPort(port uint16) model.ServiceFingerprint {
+	fp := model.ServiceFingerprint{Port: port, Service: ServiceUnknown}
+	addr, banner, ok := readBanner(port)
+	if !ok {
+		fp.Error = i18n.T("fingerprint.unreachable")
+		return fp
+	}
+	fp.Address = addr
+	if parseBanner(&fp, banner) {
+		return fp
+	}
+	for _, probe := range []func(*model.ServiceFingerprint) bool{probeTLS, probeHTTP, probeRedis, probePostgres} {
+		if probe(&fp) {
+			return fp
+		}
+	}
+	if len(banner) > 0 {
+		fp.Banner = printable(banner)
+	}
+	return fp
+}
+
+// readBanner 连接回环地址并读取服务端主动发送的数据，返回可连通的地址
+func readBanner(port uint16) (string, []byte, bool) {
+	for _, host := range []string{"127.0.0.1", "::1"} {
+		addr := net.JoinHostPort(host, strconv.Itoa(int(port)))
+		conn, err := net.DialTimeout("tcp", addr, fingerprintDialTimeout)
+		if err != nil {
+			continue
+		}
+		defer conn.Close()
+		_ = conn.SetReadDeadline(time.Now().Add(fingerprintBannerTimeout))
+		buf := make([]byte, 512)
+		n, _ := conn.Read(buf)
+		return addr, buf[:n], true
+	}
+	return "", nil, false
+}
+
+// parseBanner 识别主动发送欢迎信息的服务
+func parseBanner(fp *model.ServiceFingerprint, banner []byte) bool {
+	if bytes.HasPrefix(banner, []byte("SSH-")) {
+		fp.Service = ServiceSSH
+		line, _, _ := bytes.Cut(banner, []byte("\n"))
+		fp.Version = strings.TrimSpace(string(line))
+		return true
+	}
+	// MySQL 握手包：3 字节长度 + 序号 0 + 协议版本 10 + 以 0 结尾的服务端版本；
+	// 0xff 为拒绝连接的错误包
+	if len(banner) > 5 && banner[3] == 0 && (banner[4] == 10 || banner[4] == 0xff) {
+		length := int(banner[0]) | int(banner[1])<<8 | int(banner[2])<<16
+		if length+4 >= len(banner) {
+			fp.Service = ServiceMySQL
+			if banner[4] == 10 {
+				version, _, _ := bytes.Cut(banner[5:], []byte{0})
+				fp.Version = string(version)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// probeHTTP 发送 GET / 并读取 Server 头和页面标题
+func probeHTTP(fp *model.ServiceFingerprint) bool {
+	conn, err := net.DialTimeout("tcp", fp.Address, fingerprintDialTimeout)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	return readHTTP(fp, conn, ServiceHTTP)
+}
+
+// probeTLS 完成 TLS 握手并读取证书信息，之后尝试 HTTPS
+func probeTLS(fp *model.ServiceFingerprint) bool {
+	raw, err := net.DialTimeout("tcp", fp.Address, fingerprintDialTimeout)
+	if err != nil {
+		return false
+	}
+	defer raw.Close()
+	_ = raw.SetDeadline(time.Now().Add(fingerprintReadTimeout))
+	// 只读取证书信息，不校验（本机服务多为自签名证书）
+	conn := tls.Client(raw, &tls.Config{InsecureSkipVerify: true, ServerName: "localhost"})
+	if err := conn.Handshake(); err != nil {
+		return false
+	}
+	fp.Service = ServiceTLS
+	if certs := conn.ConnectionState().PeerCertificates; len(certs) > 0 {
+		cert := certs[0]
+		fp.TLSSubject = cert.Subject.String()
+		fp.TLSIssuer = cert.Issuer.String()
+		fp.TLSNotAfter = cert.NotAfter.Local().Format(time.RFC3339)
+		fp.TLSExpired = time.Now().After(cert.NotAfter)
+	}
+	readHTTP(fp, conn, ServiceHTTPS)
+	return true
+}
+
+// readHTTP 在已建立的连接上发送请求，是 HTTP 响应时填充 fp
+func readHTTP(fp *model.ServiceFingerprint, conn net.Conn, service string) bool {
+	_ = conn.SetDeadline(time.Now().Add(fingerprintReadTimeout))
+	req := fmt.Sprintf("GET / HTTP/1.0\r\nHost: %s\r\nUser-Agent: WinCleaner\r\nAccept: */*\r\nConnection: close\r\n\r\n", fp.Address)
+	if _, err := io.WriteString(conn, req); err != nil {
+		return false
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	fp.Service = service
+	fp.HTTPStatus = resp.StatusCode
+	fp.Server = resp.Header.Get("Server")
+	if strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxTitleBody))
+		if m := titlePattern.FindSubmatch(body); m != nil {
+			fp.Title = strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+		}
+	}
+	return true
+}
+
+// probeRedis 发送 PING，Redis 回复 +PONG 或（需要认证时）-NOAUTH
+func probeRedis(fp *model.ServiceFingerprint) bool {
+	reply := exchange(fp.Address, []byte("PING\r\n"))
+	if bytes.HasPrefix(reply, []byte("+PONG")) || bytes.HasPrefix(reply, []byte("-NOAUTH")) || bytes.HasPrefix(reply, []byte("-DENIED")) {
+		fp.Service = ServiceRedis
+		return true
+	}
+	return false
+}
+
+// probePostgres 发送 SSLRequest，PostgreSQL 回复单字节 S 或 N
+func probePostgres(fp *model.ServiceFingerprint) bool {
+	reply := exchange(fp.Address, []byte{0, 0, 0, 8, 0x04, 0xd2, 0x16, 0x2f})
+	if len(reply) == 1 && (reply[0] == 'S' || reply[0] == 'N') {
+		fp.Service = ServicePostgres
+		return true
+	}
+	return false
+}
+
+// exchange 新建连接发送 req 并读取一次回复
+func exchange(addr string, req []byte) []byte {
+	conn, err := net.DialTimeout("tcp", addr, fingerprintDialTimeout)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(fingerprintReadTimeout))
+	if _, err := conn.Write(req); err != nil {
+		return nil
+	}
+	buf := make([]byte, 256)
+	n, _ := conn.Read(buf)
+	return buf[:n]
+}
+
+// printable 把未识别的欢迎信息转换为可显示的文本，最多 80 个字符（按 rune 截断，不拆开多字节字符）
+func printable(b []byte) string {
+	s := strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return '.'
+		}
+		return r
+	}, string(b))
+	if r := []rune(s); len(r) > 80 {
+		s = string(r[:80])
+	}
+	return s
+}
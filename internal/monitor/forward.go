@@ -0,0 +1,400 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+)
+
+const (
+	portForwardFile      = "port_forwards.json"
+	forwardDialTimeout   = 5 * time.Second
+	maxPortForwards      = 50
+	forwardCopyBufferLen = 32 << 10
+	forwardIdleTimeout   = 10 * time.Minute // 双向都没有数据时断开
+	forwardHalfClose     = time.Minute      // 一方关闭写入后，另一方仍无数据时断开
+)
+
+// forwarder 一条运行中（或启动失败）的端口转发
+type forwarder struct {
+	rule     model.PortForwardRule
+	listener net.Listener
+	err      string // 启动失败原因（恢复已保存的转发时）
+
+	active   atomic.Int64
+	total    atomic.Int64
+	bytesIn  atomic.Uint64 // 客户端 → 目标
+	bytesOut atomic.Uint64 // 目标 → 客户端
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+var (
+	forwardMu      sync.Mutex
+	forwarders     = make(map[string]*forwarder)
+	forwardPending int // 已占用名额、正在启动的转发数
+)
+
+// CreatePortForward 新建 TCP 端口转发 listenAddr → targetAddr，persist 为 true 时下次启动自动恢复
+func CreatePortForward(listenAddr, targetAddr string, persist bool) (*model.PortForwardStatus, error) {
+	if err := validateForwardAddr(listenAddr); err != nil {
+		return nil, err
+	}
+	if err := validateForwardAddr(targetAddr); err != nil {
+		return nil, err
+	}
+	if forwardLoops(listenAddr, targetAddr) {
+		return nil, i18n.Errorf("forward.loop", targetAddr, listenAddr)
+	}
+
+	// 先占名额再启动，并发创建时也不会超过上限
+	forwardMu.Lock()
+	if len(forwarders)+forwardPending >= maxPortForwards {
+		forwardMu.Unlock()
+		return nil, i18n.Errorf("forward.too_many", maxPortForwards)
+	}
+	forwardPending++
+	forwardMu.Unlock()
+	defer func() {
+		forwardMu.Lock()
+		forwardPending--
+		forwardMu.Unlock()
+	}()
+
+	rule := model.PortForwardRule{
+		ID:         fmt.Sprintf("fwd-%d", time.Now().UnixNano()),
+		ListenAddr: listenAddr,
+		TargetAddr: targetAddr,
+		Persist:    persist,
+		Created:    timeutil.Now(),
+	}
+	f, err := startForwarder(rule)
+	if err != nil {
+		return nil, err
+	}
+	if persist {
+		if err := savePortForward(rule); err != nil {
+			f.stop()
+			return nil, err
+		}
+	}
+	forwardMu.Lock()
+	forwarders[rule.ID] = f
+	forwardMu.Unlock()
+	st := f.status()
+	return &st, nil
+}
+
+// ListPortForwards 所有端口转发及其连接数、流量
+func ListPortForwards() []model.PortForwardStatus {
+	forwardMu.Lock()
+	defer forwardMu.Unlock()
+	list := make([]model.PortForwardStatus, 0, len(forwarders))
+	for _, f := range forwarders {
+		list = append(list, f.status())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Created < list[j].Created })
+	return list
+}
+
+// StopPortForward 停止并删除端口转发（已保存的也一并删除）
+func StopPortForward(id string) error {
+	forwardMu.Lock()
+	f, ok := forwarders[id]
+	delete(forwarders, id)
+	forwardMu.Unlock()
+	if !ok {
+		return i18n.Errorf("forward.not_found", id)
+	}
+	f.stop()
+	if !f.rule.Persist {
+		return nil
+	}
+	var rules []model.PortForwardRule
+	return storage.Update(portForwardFile, &rules, func() error {
+		kept := rules[:0]
+		for _, r := range rules {
+			if r.ID != id {
+				kept = append(kept, r)
+			}
+		}
+		rules = kept
+		return nil
+	})
+}
+
+// RestorePortForwards 启动已保存的端口转发；启动失败的保留在列表中并显示原因
+func RestorePortForwards() error {
+	var rules []model.PortForwardRule
+	if err := storage.Load(portForwardFile, &rules); err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		f, err := startForwarder(rule)
+		if err != nil {
+			f = &forwarder{rule: rule, err: err.Error()}
+		}
+		forwardMu.Lock()
+		forwarders[rule.ID] = f
+		forwardMu.Unlock()
+	}
+	return nil
+}
+
+// CloseAllPortForwards 关闭所有转发（程序退出时调用，已保存的规则保留）
+func CloseAllPortForwards() {
+	forwardMu.Lock()
+	all := forwarders
+	forwarders = make(map[string]*forwarder)
+	forwardMu.Unlock()
+	for _, f := range all {
+		f.stop()
+	}
+}
+
+func savePortForward(rule model.PortForwardRule) error {
+	var rules []model.PortForwardRule
+	return storage.Update(portForwardFile, &rules, func() error {
+		rules = append(rules, rule)
+		return nil
+	})
+}
+
+func validateForwardAddr(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return i18n.Errorf("forward.invalid_addr", addr)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return i18n.Errorf("forward.invalid_addr", addr)
+	}
+	return nil
+}
+
+// forwardLoops 目标地址是否解析到监听地址本身：端口相同，且目标 IP 与监听 IP 相同，
+// 或监听 0.0.0.0 / :: 时目标为回环地址或本机网卡地址（如 0.0.0.0:9000 → localhost:9000）
+func forwardLoops(listenAddr, targetAddr string) bool {
+	listenHost, listenPort, _ := net.SplitHostPort(listenAddr)
+	targetHost, targetPort, _ := net.SplitHostPort(targetAddr)
+	if listenPort != targetPort {
+		return false
+	}
+	targets := resolveForwardHost(targetHost)
+	listens := resolveForwardHost(listenHost)
+	for _, l := range listens {
+		for _, t := range targets {
+			if l.Equal(t) {
+				return true
+			}
+		}
+	}
+
+	wildcard := false
+	for _, l := range listens {
+		wildcard = wildcard || l.IsUnspecified()
+	}
+	if !wildcard {
+		return false
+	}
+	var local []net.IP
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			if n, ok := a.(*net.IPNet); ok {
+				local = append(local, n.IP)
+			}
+		}
+	}
+	for _, t := range targets {
+		if t.IsLoopback() || t.IsUnspecified() {
+			return true
+		}
+		for _, ip := range local {
+			if ip.Equal(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveForwardHost 解析主机名，失败时返回空（之后的监听或连接会报出错误）
+func resolveForwardHost(host string) []net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), forwardDialTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, a := range addrs {
+		ips = append(ips, a.IP)
+	}
+	return ips
+}
+
+func startForwarder(rule model.PortForwardRule) (*forwarder, error) {
+	l, err := net.Listen("tcp", rule.ListenAddr)
+	if err != nil {
+		return nil, i18n.Errorf("forward.listen_failed", rule.ListenAddr, err)
+	}
+	f := &forwarder{rule: rule, listener: l, conns: make(map[net.Conn]struct{})}
+	go f.serve()
+	return f, nil
+}
+
+func (f *forwarder) serve() {
+	for {
+		client, err := f.listener.Accept()
+		if err != nil {
+			// 监听已关闭
+			return
+		}
+		go f.handle(client)
+	}
+}
+
+// handle 连接目标地址并双向转发，一方关闭写入后等待另一方结束
+func (f *forwarder) handle(client net.Conn) {
+	f.total.Add(1)
+	f.active.Add(1)
+	defer f.active.Add(-1)
+
+	target, err := net.DialTimeout("tcp", f.rule.TargetAddr, forwardDialTimeout)
+	if err != nil {
+		client.Close()
+		return
+	}
+	if !f.track(client, target) {
+		client.Close()
+		target.Close()
+		return
+	}
+	defer f.untrack(client, target)
+
+	idle := &pipeIdle{}
+	idle.touch()
+	done := make(chan struct{}, 2)
+	go pipe(target, client, &f.bytesIn, idle, done)
+	go pipe(client, target, &f.bytesOut, idle, done)
+	<-done
+	<-done
+}
+
+// pipeIdle 一条转发连接两个方向共用的空闲计时
+type pipeIdle struct {
+	last       atomic.Int64 // 最近一次收到数据的时间（UnixNano）
+	halfClosed atomic.Bool  // 已有一个方向结束
+}
+
+func (p *pipeIdle) touch() {
+	p.last.Store(time.Now().UnixNano())
+}
+
+// deadline 读超时时间：双向都空闲 forwardIdleTimeout 后断开，半关闭后缩短为 forwardHalfClose
+func (p *pipeIdle) deadline() time.Time {
+	limit := forwardIdleTimeout
+	if p.halfClosed.Load() {
+		limit = forwardHalfClose
+	}
+	return time.Unix(0, p.last.Load()).Add(limit)
+}
+
+// pipe 从 src 复制到 dst 并计数，结束后关闭 dst 的写方向
+//
+// 读超时只在两个方向都没有数据时生效，单向的长时间传输（如下载）不会被断开；
+// 对端半关闭后迟迟不结束的连接在 forwardHalfClose 后关闭，避免协程泄漏。
+func pipe(dst, src net.Conn, counter *atomic.Uint64, idle *pipeIdle, done chan<- struct{}) {
+	buf := make([]byte, forwardCopyBufferLen)
+	for {
+		_ = src.SetReadDeadline(idle.deadline())
+		n, err := src.Read(buf)
+		if n > 0 {
+			idle.touch()
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				break
+			}
+			counter.Add(uint64(n))
+		}
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() && time.Now().Before(idle.deadline()) {
+				// 另一个方向仍有数据，继续等待
+				continue
+			}
+			break
+		}
+	}
+	if tc, ok := dst.(*net.TCPConn); ok {
+		_ = tc.CloseWrite()
+	} else {
+		_ = dst.Close()
+	}
+	// 另一个方向按半关闭超时重新计算读超时
+	if idle.halfClosed.CompareAndSwap(false, true) {
+		_ = dst.SetReadDeadline(idle.deadline())
+	}
+	done <- struct{}{}
+}
+
+// track 记录活动连接，转发已停止时返回 false
+func (f *forwarder) track(conns ...net.Conn) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conns == nil {
+		return false
+	}
+	for _, c := range conns {
+		f.conns[c] = struct{}{}
+	}
+	return true
+}
+
+func (f *forwarder) untrack(conns ...net.Conn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range conns {
+		c.Close()
+		delete(f.conns, c)
+	}
+}
+
+// stop 关闭监听和所有活动连接
+func (f *forwarder) stop() {
+	if f.listener != nil {
+		f.listener.Close()
+	}
+	f.mu.Lock()
+	conns := f.conns
+	f.conns = nil
+	f.mu.Unlock()
+	for c := range conns {
+		c.Close()
+	}
+}
+
+func (f *forwarder) status() model.PortForwardStatus {
+	return model.PortForwardStatus{
+		PortForwardRule: f.rule,
+		Running:         f.listener != nil,
+		Error:           f.err,
+		Active:          int(f.active.Load()),
+		Total:           int(f.total.Load()),
+		BytesIn:         f.bytesIn.Load(),
+		BytesOut:        f.bytesOut.Load(),
+	}
+}
@@ -0,0 +1,251 @@
+package monitor
+
+import (
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/timeutil"
+)
+
+const maxFreePortCount = 100
+
+// heldPort 为调用方占住的端口
+type heldPort struct {
+	info      model.HeldPort
+	listeners []io.Closer
+}
+
+var (
+	heldMu    sync.Mutex
+	heldPorts = make(map[uint16]*heldPort)
+)
+
+// FindFreePorts 在端口范围内查找可用端口
+//
+// 跳过连接表中已监听的端口和系统保留/排除的端口（Windows 上 Hyper-V 等通过
+// excludedportrange 保留的范围），并在每个地址上实际绑定一次确认可用。
+// req.Hold 为 true 时保持监听，直到 ReleasePort。
+func FindFreePorts(req model.FreePortRequest) (*model.FreePortResult, error) {
+	if req.Start == 0 || req.End < req.Start {
+		return nil, i18n.Errorf("freeport.invalid_range")
+	}
+	protos, err := freePortProtos(req.Proto)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := bindAddresses(req.Interfaces)
+	if err != nil {
+		return nil, err
+	}
+	count := req.Count
+	if count <= 0 {
+		count = 1
+	}
+	if count > maxFreePortCount {
+		count = maxFreePortCount
+	}
+
+	result := &model.FreePortResult{
+		Ports:     []uint16{},
+		Addresses: addrs,
+		Excluded:  []model.PortRange{},
+		Held:      req.Hold,
+	}
+	for _, proto := range protos {
+		for _, r := range excludedPortRanges(proto) {
+			if r.End >= req.Start && r.Start <= req.End {
+				result.Excluded = append(result.Excluded, r)
+			}
+		}
+	}
+	listening := listeningPorts()
+
+	for port := int(req.Start); port <= int(req.End) && len(result.Ports) < count; port++ {
+		p := uint16(port)
+		if inRanges(result.Excluded, p) {
+			result.Reserved++
+			continue
+		}
+		if portListened(listening, protos, p) {
+			result.InUse++
+			continue
+		}
+		listeners, ok := bindAll(protos, addrs, p)
+		if !ok {
+			result.InUse++
+			continue
+		}
+		if req.Hold {
+			holdPort(p, req.Proto, addrs, listeners)
+		} else {
+			closeAll(listeners)
+		}
+		result.Ports = append(result.Ports, p)
+	}
+	return result, nil
+}
+
+// GetHeldPorts 当前占住的端口
+func GetHeldPorts() []model.HeldPort {
+	heldMu.Lock()
+	defer heldMu.Unlock()
+	ports := make([]model.HeldPort, 0, len(heldPorts))
+	for _, h := range heldPorts {
+		ports = append(ports, h.info)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+	return ports
+}
+
+// ReleasePort 释放 FindFreePorts 占住的端口
+func ReleasePort(port uint16) error {
+	heldMu.Lock()
+	h, ok := heldPorts[port]
+	delete(heldPorts, port)
+	heldMu.Unlock()
+	if !ok {
+		return i18n.Errorf("freeport.not_held", port)
+	}
+	closeAll(h.listeners)
+	return nil
+}
+
+// ReleaseAllPorts 释放全部占住的端口（程序退出时调用）
+func ReleaseAllPorts() {
+	heldMu.Lock()
+	held := heldPorts
+	heldPorts = make(map[uint16]*heldPort)
+	heldMu.Unlock()
+	for _, h := range held {
+		closeAll(h.listeners)
+	}
+}
+
+func holdPort(port uint16, proto string, addrs []string, listeners []io.Closer) {
+	if proto == "" {
+		proto = "tcp"
+	}
+	heldMu.Lock()
+	defer heldMu.Unlock()
+	heldPorts[port] = &heldPort{
+		info:      model.HeldPort{Port: port, Proto: proto, Addresses: addrs, Since: timeutil.Now()},
+		listeners: listeners,
+	}
+}
+
+func freePortProtos(proto string) ([]string, error) {
+	switch proto {
+	case "", "tcp":
+		return []string{"tcp"}, nil
+	case "udp":
+		return []string{"udp"}, nil
+	case "both":
+		return []string{"tcp", "udp"}, nil
+	}
+	return nil, i18n.Errorf("freeport.invalid_proto", proto)
+}
+
+// bindAddresses 把网卡名或 IP 转换为要绑定的地址，为空时为所有 IPv4 地址
+func bindAddresses(interfaces []string) ([]string, error) {
+	if len(interfaces) == 0 {
+		return []string{"0.0.0.0"}, nil
+	}
+	var addrs []string
+	seen := make(map[string]bool)
+	add := func(ip string) {
+		if !seen[ip] {
+			seen[ip] = true
+			addrs = append(addrs, ip)
+		}
+	}
+	for _, name := range interfaces {
+		if ip := net.ParseIP(name); ip != nil {
+			add(ip.String())
+			continue
+		}
+		iface, err := net.InterfaceByName(name)
+		if err != nil {
+			return nil, i18n.Errorf("freeport.invalid_interface", name)
+		}
+		ifAddrs, _ := iface.Addrs()
+		for _, a := range ifAddrs {
+			ipNet, ok := a.(*net.IPNet)
+			// IPv6 链路本地地址需要带 zone 才能绑定，跳过
+			if !ok || (ipNet.IP.To4() == nil && ipNet.IP.IsLinkLocalUnicast()) {
+				continue
+			}
+			add(ipNet.IP.String())
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, i18n.Errorf("freeport.no_address")
+	}
+	return addrs, nil
+}
+
+// listeningPorts 连接表中正在监听的端口，按协议区分
+func listeningPorts() map[string]map[uint16]bool {
+	ports := map[string]map[uint16]bool{"tcp": {}, "udp": {}}
+	conns, err := ListConnections(model.PortFilter{ListeningOnly: true})
+	if err != nil {
+		return ports
+	}
+	for _, c := range conns.Ports {
+		ports[c.Proto][c.Port] = true
+	}
+	return ports
+}
+
+func portListened(listening map[string]map[uint16]bool, protos []string, port uint16) bool {
+	for _, proto := range protos {
+		if listening[proto][port] {
+			return true
+		}
+	}
+	return false
+}
+
+// bindAll 在所有地址上按协议绑定端口，任一失败时关闭已打开的监听
+func bindAll(protos, addrs []string, port uint16) ([]io.Closer, bool) {
+	var listeners []io.Closer
+	for _, proto := range protos {
+		for _, addr := range addrs {
+			hostPort := net.JoinHostPort(addr, strconv.Itoa(int(port)))
+			var (
+				l   io.Closer
+				err error
+			)
+			if proto == "tcp" {
+				l, err = net.Listen("tcp", hostPort)
+			} else {
+				l, err = net.ListenPacket("udp", hostPort)
+			}
+			if err != nil {
+				closeAll(listeners)
+				return nil, false
+			}
+			listeners = append(listeners, l)
+		}
+	}
+	return listeners, true
+}
+
+func closeAll(listeners []io.Closer) {
+	for _, l := range listeners {
+		_ = l.Close()
+	}
+}
+
+func inRanges(ranges []model.PortRange, port uint16) bool {
+	for _, r := range ranges {
+		if port >= r.Start && port <= r.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package monitor
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"win-cleaner/internal/model"
+)
+
+// excludedPortRanges 内核保留的端口（net.ipv4.ip_local_reserved_ports，TCP/UDP 共用）
+func excludedPortRanges(proto string) []model.PortRange {
+	data, err := os.ReadFile("/proc/sys/net/ipv4/ip_local_reserved_ports")
+	if err != nil {
+		return nil
+	}
+	var ranges []model.PortRange
+	for _, part := range strings.Split(strings.TrimSpace(string(data)), ",") {
+		lo, hi, found := strings.Cut(part, "-")
+		if !found {
+			hi = lo
+		}
+		start, err1 := strconv.ParseUint(lo, 10, 16)
+		end, err2 := strconv.ParseUint(hi, 10, 16)
+		if err1 != nil || err2 != nil || end < start {
+			continue
+		}
+		ranges = append(ranges, model.PortRange{Start: uint16(start), End: uint16(end), Proto: proto})
+	}
+	return ranges
+}
@@ -0,0 +1,40 @@
+package monitor
+
+import (
+	"strconv"
+	"strings"
+
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/winapi"
+)
+
+// excludedPortRanges 系统排除的端口范围（Hyper-V、WinNAT 等通过 netsh 保留，IPv4 和 IPv6 合并）
+func excludedPortRanges(proto string) []model.PortRange {
+	var ranges []model.PortRange
+	for _, family := range []string{"ipv4", "ipv6"} {
+		out, err := winapi.HiddenCmd("netsh", "int", family, "show", "excludedportrange", "protocol="+proto).Output()
+		if err != nil {
+			continue
+		}
+		ranges = append(ranges, parseExcludedPortRanges(string(out), proto)...)
+	}
+	return ranges
+}
+
+// parseExcludedPortRanges 解析 netsh 输出中的 "起始端口 结束端口" 行
+func parseExcludedPortRanges(out, proto string) []model.PortRange {
+	var ranges []model.PortRange
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		start, err1 := strconv.ParseUint(fields[0], 10, 16)
+		end, err2 := strconv.ParseUint(fields[1], 10, 16)
+		if err1 != nil || err2 != nil || end < start {
+			continue
+		}
+		ranges = append(ranges, model.PortRange{Start: uint16(start), End: uint16(end), Proto: proto})
+	}
+	return ranges
+}
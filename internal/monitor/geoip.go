@@ -0,0 +1,300 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/internal/settings"
+	"win-cleaner/pkg/datadir"
+	"win-cleaner/pkg/timeutil"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+const (
+	GeoSourceOffline = "offline"
+	GeoSourceOnline  = "online"
+
+	// ip-api.com 的免费接口只提供 HTTP，IP 以明文发送；界面上的开关标明了未加密，默认关闭。
+	// 因此只查询用户逐个点选的 IP 和本机公网 IP，连接历史、端口列表等批量结果只查本地数据库。
+	geoOnlineBatchURL = "http://ip-api.com/batch?fields=status,query,countryCode,country,regionName,city,as,isp"
+	maxGeoOnlineCache = 5000
+)
+
+// geoRecord GeoLite2 City/Country/ASN 与 DB-IP lite 共用的字段
+type geoRecord struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	ASN   uint   `maxminddb:"autonomous_system_number"`
+	ASOrg string `maxminddb:"autonomous_system_organization"`
+}
+
+// geoDB 数据目录中的一个 .mmdb 文件
+type geoDB struct {
+	file   string
+	reader *maxminddb.Reader
+}
+
+var (
+	geoMu     sync.Mutex
+	geoDBs    []geoDB
+	geoFiles  string // 已打开的文件名、大小和修改时间，变化时重新加载
+	geoOnline = make(map[string]model.GeoIPInfo)
+)
+
+// LookupGeoIP 查询 IP 的国家、城市和 ASN
+//
+// 只查数据目录中的 .mmdb 数据库（GeoLite2 City/Country/ASN 或 DB-IP lite），完全离线，
+// 供连接历史、端口列表等批量查询使用。内网、回环等非公网地址不查询。
+func LookupGeoIP(ips []string) []model.GeoIPInfo {
+	results := make([]model.GeoIPInfo, 0, len(ips))
+	geoMu.Lock()
+	defer geoMu.Unlock()
+	loadGeoDBs()
+	for _, s := range ips {
+		results = append(results, lookupGeoLocked(s))
+	}
+	return results
+}
+
+// LookupGeoIPOnline 查询单个 IP，本地查不到、设置允许在线查询且未开启隐私模式时请求 ip-api.com
+//
+// 只用于用户主动查询的 IP 和本机公网 IP，不要在循环中批量调用。
+func LookupGeoIPOnline(s string) model.GeoIPInfo {
+	geoMu.Lock()
+	loadGeoDBs()
+	info := lookupGeoLocked(s)
+	cached, ok := geoOnline[s]
+	geoMu.Unlock()
+	switch {
+	case info.Source != "" || info.Private || net.ParseIP(s) == nil || !geoOnlineAllowed():
+		return info
+	case ok:
+		return cached
+	}
+	return lookupOnline(s)
+}
+
+// lookupGeoLocked 离线查询一个 IP，调用方需持有 geoMu
+func lookupGeoLocked(s string) model.GeoIPInfo {
+	info := model.GeoIPInfo{IP: s}
+	ip := net.ParseIP(s)
+	switch {
+	case ip == nil:
+	case !isPublicIP(ip):
+		info.Private = true
+	default:
+		lookupOffline(ip, &info)
+	}
+	return info
+}
+
+// GetGeoIPStatus 已加载的 GeoIP 数据库及在线查询设置
+func GetGeoIPStatus() model.GeoIPStatus {
+	geoMu.Lock()
+	defer geoMu.Unlock()
+	loadGeoDBs()
+	st := model.GeoIPStatus{
+		Dir:            datadir.Get(),
+		Databases:      []model.GeoIPDatabase{},
+		OnlineFallback: geoOnlineAllowed(),
+	}
+	for _, db := range geoDBs {
+		meta := db.reader.Metadata
+		st.Databases = append(st.Databases, model.GeoIPDatabase{
+			File:      filepath.Base(db.file),
+			Type:      meta.DatabaseType,
+			BuildTime: timeutil.Format(time.Unix(int64(meta.BuildEpoch), 0)),
+		})
+	}
+	return st
+}
+
+// lookupOffline 依次查询各数据库，合并地理位置和 ASN 信息（调用方持有 geoMu 并已调用 loadGeoDBs）
+func lookupOffline(ip net.IP, info *model.GeoIPInfo) bool {
+	found := false
+	for _, db := range geoDBs {
+		var rec geoRecord
+		if err := db.reader.Lookup(ip, &rec); err != nil {
+			continue
+		}
+		if rec.Country.ISOCode != "" && info.CountryCode == "" {
+			info.CountryCode = rec.Country.ISOCode
+			info.Country = localName(rec.Country.Names)
+			if len(rec.Subdivisions) > 0 {
+				info.Region = localName(rec.Subdivisions[0].Names)
+			}
+			info.City = localName(rec.City.Names)
+			found = true
+		}
+		if rec.ASN != 0 && info.ASN == 0 {
+			info.ASN = rec.ASN
+			info.ASOrg = rec.ASOrg
+			found = true
+		}
+	}
+	if found {
+		info.Source = GeoSourceOffline
+	}
+	return found
+}
+
+// loadGeoDBs 打开数据目录下的 .mmdb 文件；文件增删或大小、修改时间变化后自动重新加载（调用方持有 geoMu）
+//
+// 数据库以内存映射方式打开，Windows 下打开期间无法覆盖或删除。更新数据库时应换一个文件名放入，
+// 下次查询加载新文件并关闭旧文件后再删除旧文件；或者退出程序后再覆盖。
+func loadGeoDBs() {
+	files, _ := filepath.Glob(filepath.Join(datadir.Get(), "*.mmdb"))
+	sort.Strings(files)
+	var b strings.Builder
+	for _, f := range files {
+		b.WriteString(f)
+		if fi, err := os.Stat(f); err == nil {
+			fmt.Fprintf(&b, "|%d|%d", fi.Size(), fi.ModTime().UnixNano())
+		}
+		b.WriteByte('\n')
+	}
+	key := b.String()
+	if key == geoFiles && (geoDBs != nil || len(files) == 0) {
+		return
+	}
+	for _, db := range geoDBs {
+		db.reader.Close()
+	}
+	geoDBs = []geoDB{}
+	geoFiles = key
+	for _, f := range files {
+		r, err := maxminddb.Open(f)
+		if err != nil {
+			continue
+		}
+		geoDBs = append(geoDBs, geoDB{file: f, reader: r})
+	}
+	// City 库排在 Country 库前面，优先得到城市信息
+	sort.SliceStable(geoDBs, func(i, j int) bool {
+		return strings.Contains(geoDBs[i].reader.Metadata.DatabaseType, "City") &&
+			!strings.Contains(geoDBs[j].reader.Metadata.DatabaseType, "City")
+	})
+}
+
+// localName 按界面语言取名称，没有时用英文
+func localName(names map[string]string) string {
+	lang := "en"
+	if i18n.Locale() == "zh-CN" {
+		lang = "zh-CN"
+	}
+	if n := names[lang]; n != "" {
+		return n
+	}
+	return names["en"]
+}
+
+// lookupOnline 通过 ip-api.com 查询本地数据库中没有的 IP（成功的结果缓存在内存中）
+func lookupOnline(ip string) model.GeoIPInfo {
+	for _, info := range fetchOnlineBatch([]string{ip}) {
+		if info.IP != ip {
+			continue
+		}
+		geoMu.Lock()
+		if len(geoOnline) < maxGeoOnlineCache {
+			geoOnline[ip] = info
+		}
+		geoMu.Unlock()
+		return info
+	}
+	return model.GeoIPInfo{IP: ip}
+}
+
+func fetchOnlineBatch(ips []string) []model.GeoIPInfo {
+	body, _ := json.Marshal(ips)
+	lang := "en"
+	if i18n.Locale() == "zh-CN" {
+		lang = "zh-CN"
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(geoOnlineBatchURL+"&lang="+lang, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var items []struct {
+		Status      string `json:"status"`
+		Query       string `json:"query"`
+		CountryCode string `json:"countryCode"`
+		Country     string `json:"country"`
+		RegionName  string `json:"regionName"`
+		City        string `json:"city"`
+		AS          string `json:"as"` // 如 "AS15169 Google LLC"
+		ISP         string `json:"isp"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil
+	}
+	infos := make([]model.GeoIPInfo, 0, len(items))
+	for _, it := range items {
+		if it.Status != "success" {
+			continue
+		}
+		info := model.GeoIPInfo{
+			IP:          it.Query,
+			CountryCode: it.CountryCode,
+			Country:     it.Country,
+			Region:      it.RegionName,
+			City:        it.City,
+			ASOrg:       it.ISP,
+			Source:      GeoSourceOnline,
+		}
+		if num, org, ok := strings.Cut(it.AS, " "); ok && strings.HasPrefix(num, "AS") {
+			if n, err := strconv.ParseUint(num[2:], 10, 32); err == nil {
+				info.ASN = uint(n)
+				info.ASOrg = org
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// geoOnlineAllowed 设置允许在线查询且未开启隐私模式
+func geoOnlineAllowed() bool {
+	s := settings.Get()
+	return s.GeoIPOnline && !s.PrivacyMode
+}
+
+// isPublicIP 是否为公网地址
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsMulticast())
+}
+
+// geoLocation 拼接 "国家 地区 城市"（空项省略）
+func geoLocation(info model.GeoIPInfo) string {
+	var parts []string
+	for _, p := range []string{info.Country, info.Region, info.City} {
+		if p != "" && (len(parts) == 0 || parts[len(parts)-1] != p) {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"win-cleaner/internal/i18n"
 	"win-cleaner/internal/model"
 	"win-cleaner/pkg/winapi"
 )
@@ -105,9 +106,9 @@ foreach ($a in $adapters) {
 
 	if len(gpus) == 0 {
 		gpus = append(gpus, model.GPUInfo{
-			Name:      "未检测到显卡",
+			Name:      i18n.T("gpu.none_detected"),
 			Type:      "none",
-			TypeLabel: "无显卡",
+			TypeLabel: i18n.T("gpu.none"),
 		})
 	}
 
@@ -121,18 +122,18 @@ func classifyGPU(name string) (string, string) {
 	// 先检查核显（因为有些名字同时包含厂商名）
 	for _, kw := range integratedKeywords {
 		if strings.Contains(lower, kw) {
-			return "integrated", "核显"
+			return "integrated", i18n.T("gpu.integrated")
 		}
 	}
 
 	// 再检查独显
 	for _, kw := range discreteKeywords {
 		if strings.Contains(lower, kw) {
-			return "discrete", "独显"
+			return "discrete", i18n.T("gpu.discrete")
 		}
 	}
 
 	// 有显存大于 512MB 的大概率是独显
 	// 这里无法拿到 vram 参数，默认归为核显
-	return "integrated", "核显"
+	return "integrated", i18n.T("gpu.integrated")
 }
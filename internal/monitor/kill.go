@@ -0,0 +1,190 @@
+package monitor
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"time"
+
+	"win-cleaner/internal/model"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// 单个进程的结束结果
+const (
+	KillStatusKilled       = "killed"         // 已结束
+	KillStatusExited       = "already_exited" // 操作前进程已退出
+	KillStatusAccessDenied = "access_denied"  // 权限不足
+	KillStatusProtected    = "protected"      // 受保护的进程，未操作
+	KillStatusFailed       = "failed"         // 其他原因失败
+
+	KillMethodGraceful = "graceful" // 正常结束（Windows 发送关闭请求，Linux 发送 SIGTERM）
+	KillMethodForce    = "force"    // 强制结束
+)
+
+const (
+	defaultGracefulTimeout = 3 * time.Second
+	forceKillWait          = time.Second
+	exitPollInterval       = 100 * time.Millisecond
+)
+
+// KillProcessesByPort 结束占用本地端口 port 的进程，每个 PID 只处理一次
+//
+// 只匹配本地端口，连接到该端口的客户端（远端）进程不受影响。opts.PIDs 不为空时只处理其中的进程。
+func KillProcessesByPort(port uint16, opts model.KillPortOptions) (*model.KillPortResult, error) {
+	conns, err := ListConnections(model.PortFilter{
+		Proto:         opts.Proto,
+		MinPort:       port,
+		MaxPort:       port,
+		ListeningOnly: opts.ListeningOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.KillPortResult{Port: port, Results: []model.ProcessKillResult{}}
+	seen := make(map[int32]bool)
+	for _, c := range conns.Ports {
+		if seen[c.PID] || (len(opts.PIDs) > 0 && !slices.Contains(opts.PIDs, c.PID)) {
+			continue
+		}
+		seen[c.PID] = true
+
+		r := TerminateProcess(c.PID, opts)
+		r.Name = c.ProcessName
+		if r.Status == KillStatusKilled {
+			result.Killed++
+		}
+		result.Results = append(result.Results, r)
+	}
+	return result, nil
+}
+
+// TerminateProcess 先正常结束进程，超时仍未退出再强制结束；opts.Tree 时连同子进程一起结束
+func TerminateProcess(pid int32, opts model.KillPortOptions) model.ProcessKillResult {
+	r := model.ProcessKillResult{PID: pid}
+	p, err := process.NewProcess(pid)
+	if err != nil || !pidAlive(pid) {
+		r.Status = KillStatusExited
+		return r
+	}
+	if err := CheckKillAllowed(pid, opts.Override); err != nil {
+		r.Status = KillStatusProtected
+		r.Error = err.Error()
+		var pe *ProtectedProcessError
+		r.Overridable = errors.As(err, &pe) && pe.Overridable
+		return r
+	}
+
+	// 子进程先于父进程处理，避免父进程重新拉起子进程；受保护的子进程跳过
+	targets := []int32{pid}
+	if opts.Tree {
+		var children []int32
+		for _, c := range descendants(p) {
+			if CheckKillAllowed(c, opts.Override) == nil {
+				children = append(children, c)
+			}
+		}
+		r.Children = children
+		targets = append(children, pid)
+	}
+
+	timeout := time.Duration(opts.GracefulTimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultGracefulTimeout
+	}
+	if !opts.Force {
+		var gracefulErr error
+		for _, t := range targets {
+			if err := terminateGracefully(t); err != nil {
+				gracefulErr = err
+			}
+		}
+		// 正常结束请求被拒绝（如没有窗口的控制台程序）时直接强制结束
+		if gracefulErr == nil && waitExit(targets, timeout) {
+			r.Status = KillStatusKilled
+			r.Method = KillMethodGraceful
+			return r
+		}
+	}
+
+	var killErr error
+	for _, t := range targets {
+		if !pidAlive(t) {
+			continue
+		}
+		if err := killPID(t); err != nil {
+			killErr = err
+		}
+	}
+	r.Method = KillMethodForce
+	if waitExit([]int32{pid}, forceKillWait) {
+		r.Status = KillStatusKilled
+		return r
+	}
+
+	r.Status = KillStatusFailed
+	if killErr != nil {
+		r.Error = killErr.Error()
+		if errors.Is(killErr, os.ErrPermission) {
+			r.Status = KillStatusAccessDenied
+		}
+	}
+	return r
+}
+
+// descendants 所有子孙进程，子进程排在父进程之前
+func descendants(p *process.Process) []int32 {
+	children, err := p.Children()
+	if err != nil {
+		return nil
+	}
+	var pids []int32
+	for _, c := range children {
+		pids = append(pids, descendants(c)...)
+		pids = append(pids, c.Pid)
+	}
+	return pids
+}
+
+// pidAlive 进程是否仍在运行（已退出但未被回收的僵尸进程视为已退出）
+func pidAlive(pid int32) bool {
+	exists, err := process.PidExists(pid)
+	if err != nil || !exists {
+		return false
+	}
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return false
+	}
+	if status, err := p.Status(); err == nil {
+		for _, s := range status {
+			if s == process.Zombie {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// waitExit 等待所有进程退出，超时返回 false
+func waitExit(pids []int32, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		alive := false
+		for _, pid := range pids {
+			if pidAlive(pid) {
+				alive = true
+				break
+			}
+		}
+		if !alive {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(exitPollInterval)
+	}
+}
@@ -0,0 +1,12 @@
+package monitor
+
+import "github.com/shirou/gopsutil/v3/process"
+
+// terminateGracefully 向进程发送 SIGTERM
+func terminateGracefully(pid int32) error {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Terminate()
+}
@@ -0,0 +1,12 @@
+package monitor
+
+import (
+	"strconv"
+
+	"win-cleaner/pkg/winapi"
+)
+
+// terminateGracefully 请求进程正常退出（taskkill 不带 /F 会向进程窗口发送关闭消息）
+func terminateGracefully(pid int32) error {
+	return winapi.HiddenCmd("taskkill", "/PID", strconv.Itoa(int(pid))).Run()
+}
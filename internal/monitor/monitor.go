@@ -1,29 +1,23 @@
 package monitor
 
 import (
-	"encoding/json"
-	"io"
-	"net/http"
+	"context"
 	"os"
 	"runtime"
-	"sync"
 	"time"
 
 	"win-cleaner/internal/model"
+	"win-cleaner/internal/publicip"
+	"win-cleaner/internal/settings"
+	"win-cleaner/pkg/timeutil"
 
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
-// IP 信息缓存（避免每次刷新都请求外网）
-var (
-	cachedIP       string
-	cachedLocation string
-	cachedOperator string
-	ipCacheTime    time.Time
-	ipCacheMu      sync.Mutex
-)
+// ipResolver 公网 IP 查询（按设置的服务顺序失败切换，结果缓存）
+var ipResolver = &publicip.Resolver{}
 
 // GetSystemInfo 获取系统概览
 func GetSystemInfo() (*model.SystemInfo, error) {
@@ -51,8 +45,8 @@ func GetSystemInfo() (*model.SystemInfo, error) {
 		return nil, err
 	}
 
-	// 公网 IP（缓存 5 分钟）
-	ip, location, operator := getPublicIPInfo()
+	// 公网 IP（缓存时间见设置，默认 5 分钟）
+	pub := GetPublicIP(false)
 
 	return &model.SystemInfo{
 		OS:          runtime.GOOS + " " + runtime.GOARCH,
@@ -64,53 +58,41 @@ func GetSystemInfo() (*model.SystemInfo, error) {
 		DiskTotal:   diskStat.Total,
 		DiskUsed:    diskStat.Used,
 		DiskPercent: diskStat.UsedPercent,
-		PublicIP:    ip,
-		IPLocation:  location,
-		IPOperator:  operator,
+		PublicIP:    pub.IP,
+		IPLocation:  pub.Location,
+		IPOperator:  pub.Operator,
+		PrivacyMode: pub.Disabled,
 	}, nil
 }
 
-// getPublicIPInfo 获取公网 IP 信息（带缓存）
-func getPublicIPInfo() (string, string, string) {
-	ipCacheMu.Lock()
-	defer ipCacheMu.Unlock()
-
-	if cachedIP != "" && time.Since(ipCacheTime) < 5*time.Minute {
-		return cachedIP, cachedLocation, cachedOperator
+// GetPublicIP 查询公网 IP 及归属地；隐私模式下不发出请求。force 为 true 时忽略缓存
+func GetPublicIP(force bool) model.PublicIPInfo {
+	s := settings.Get()
+	if s.PrivacyMode {
+		return model.PublicIPInfo{Disabled: true}
 	}
-
-	// 使用 ip-api.com JSON 接口（免费、稳定、支持中文）
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get("http://ip-api.com/json/?lang=zh-CN&fields=query,country,regionName,city,isp")
+	providers, err := publicip.ParseProviders(s.PublicIPProviders, s.PublicIPCustomURL)
 	if err != nil {
-		return cachedIP, cachedLocation, cachedOperator
+		return model.PublicIPInfo{Error: err.Error()}
 	}
-	defer resp.Body.Close()
+	ttl := time.Duration(s.PublicIPCacheMinutes) * time.Minute
+	res, err := ipResolver.Lookup(context.Background(), providers, ttl, force)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return cachedIP, cachedLocation, cachedOperator
+	info := model.PublicIPInfo{IP: res.IP, Provider: res.Provider}
+	if !res.At.IsZero() {
+		info.CheckedAt = timeutil.Format(res.At)
 	}
-
-	var result struct {
-		Query      string `json:"query"`
-		Country    string `json:"country"`
-		RegionName string `json:"regionName"`
-		City       string `json:"city"`
-		ISP        string `json:"isp"`
-	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return cachedIP, cachedLocation, cachedOperator
+	if err != nil {
+		info.Error = err.Error()
+		info.Stale = res.IP != ""
 	}
-
-	if result.Query != "" {
-		cachedIP = result.Query
-		cachedLocation = result.Country + " " + result.RegionName + " " + result.City
-		cachedOperator = result.ISP
-		ipCacheTime = time.Now()
+	if res.IP != "" {
+		// 归属地优先查本地 GeoIP 数据库，允许时才在线查询
+		geo := LookupGeoIPOnline(res.IP)
+		info.Location = geoLocation(geo)
+		info.Operator = geo.ASOrg
 	}
-
-	return cachedIP, cachedLocation, cachedOperator
+	return info
 }
 
 // GetMemoryInfo 获取内存信息
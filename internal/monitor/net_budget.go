@@ -0,0 +1,212 @@
+package monitor
+
+import (
+	"time"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/storage"
+)
+
+const netBudgetFile = "net_budgets.json"
+
+// 提醒档位（百分比）
+var budgetThresholds = []int{50, 80, 100}
+
+// netBudgetConfig 流量套餐设置及每个套餐在当前周期已发送的提醒档位
+type netBudgetConfig struct {
+	Budgets []model.NetBudget          `json:"budgets"`
+	Alerted map[string]budgetAlertMark `json:"alerted"`
+}
+
+type budgetAlertMark struct {
+	CycleStart string `json:"cycle_start"`
+	Level      int    `json:"level"`
+}
+
+// GetNetBudgets 获取所有流量套餐在当前周期的使用情况
+func GetNetBudgets() ([]model.NetBudgetStatus, error) {
+	var cfg netBudgetConfig
+	if err := storage.Load(netBudgetFile, &cfg); err != nil {
+		return nil, err
+	}
+	return budgetStatuses(cfg.Budgets, time.Now())
+}
+
+// SetNetBudget 新增或修改网卡的流量套餐
+func SetNetBudget(b model.NetBudget) error {
+	if b.BudgetBytes == 0 {
+		return i18n.Errorf("budget.invalid_amount")
+	}
+	if b.CycleStartDay < 1 || b.CycleStartDay > 31 {
+		return i18n.Errorf("budget.invalid_start_day")
+	}
+	if b.SentWeight < 0 || b.RecvWeight < 0 {
+		return i18n.Errorf("budget.negative_weight")
+	}
+	if b.SentWeight == 0 && b.RecvWeight == 0 {
+		b.SentWeight, b.RecvWeight = 1, 1
+	}
+
+	var cfg netBudgetConfig
+	return storage.Update(netBudgetFile, &cfg, func() error {
+		for i := range cfg.Budgets {
+			if cfg.Budgets[i].Iface == b.Iface {
+				cfg.Budgets[i] = b
+				// 修改额度后重新计算提醒
+				delete(cfg.Alerted, b.Iface)
+				return nil
+			}
+		}
+		cfg.Budgets = append(cfg.Budgets, b)
+		return nil
+	})
+}
+
+// DeleteNetBudget 删除网卡的流量套餐
+func DeleteNetBudget(iface string) error {
+	var cfg netBudgetConfig
+	return storage.Update(netBudgetFile, &cfg, func() error {
+		kept := cfg.Budgets[:0]
+		for _, b := range cfg.Budgets {
+			if b.Iface != iface {
+				kept = append(kept, b)
+			}
+		}
+		cfg.Budgets = kept
+		delete(cfg.Alerted, iface)
+		return nil
+	})
+}
+
+// CheckNetBudgetAlerts 检查各套餐是否新达到 50/80/100% 档位，每个周期每档只提醒一次
+func CheckNetBudgetAlerts() ([]model.NetBudgetAlert, error) {
+	var alerts []model.NetBudgetAlert
+	var cfg netBudgetConfig
+	err := storage.Update(netBudgetFile, &cfg, func() error {
+		if len(cfg.Budgets) == 0 {
+			return nil
+		}
+		statuses, err := budgetStatuses(cfg.Budgets, time.Now())
+		if err != nil {
+			return err
+		}
+		if cfg.Alerted == nil {
+			cfg.Alerted = make(map[string]budgetAlertMark)
+		}
+		for _, st := range statuses {
+			mark := cfg.Alerted[st.Budget.Iface]
+			if mark.CycleStart != st.CycleStart {
+				mark = budgetAlertMark{CycleStart: st.CycleStart}
+			}
+			if st.Level > mark.Level {
+				alerts = append(alerts, model.NetBudgetAlert{
+					Iface:       st.Budget.Iface,
+					Threshold:   st.Level,
+					UsedBytes:   st.UsedBytes,
+					BudgetBytes: st.Budget.BudgetBytes,
+					UsedPercent: st.UsedPercent,
+					CycleStart:  st.CycleStart,
+					CycleEnd:    st.CycleEnd,
+				})
+				mark.Level = st.Level
+			}
+			cfg.Alerted[st.Budget.Iface] = mark
+		}
+		return nil
+	})
+	return alerts, err
+}
+
+// budgetStatuses 根据流量历史计算每个套餐在当前周期的用量与预测
+func budgetStatuses(budgets []model.NetBudget, now time.Time) ([]model.NetBudgetStatus, error) {
+	if len(budgets) == 0 {
+		return nil, nil
+	}
+
+	nhMu.Lock()
+	records, err := loadNetDayTotals()
+	nhMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	ifaceCfg := loadIfaceConfig()
+
+	statuses := make([]model.NetBudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		start, end := billingCycle(now, b.CycleStartDay)
+		// 近期速率：最近 7 天，周期开始不足 7 天时取周期开始至今
+		rateStart := now.AddDate(0, 0, -7)
+		if rateStart.Before(start) {
+			rateStart = start
+		} else {
+			rateStart = time.Date(rateStart.Year(), rateStart.Month(), rateStart.Day(), 0, 0, 0, 0, now.Location())
+		}
+		cycleStartDate := start.Format("2006-01-02")
+		rateStartDate := rateStart.Format("2006-01-02")
+
+		var used, recent float64
+		for _, r := range records {
+			if b.Iface == "" {
+				if !ifaceIncluded(r.Iface, ifaceCfg) {
+					continue
+				}
+			} else if r.Iface != b.Iface {
+				continue
+			}
+			weighted := float64(r.Sent)*b.SentWeight + float64(r.Recv)*b.RecvWeight
+			day := dayKey(r)
+			if day >= cycleStartDate {
+				used += weighted
+			}
+			if day >= rateStartDate {
+				recent += weighted
+			}
+		}
+
+		rateDays := now.Sub(rateStart).Hours() / 24
+		if rateDays < 1 {
+			rateDays = 1
+		}
+		dailyRate := recent / rateDays
+		remainingDays := end.Sub(now).Hours() / 24
+		projected := used + dailyRate*remainingDays
+
+		st := model.NetBudgetStatus{
+			Budget:           b,
+			CycleStart:       cycleStartDate,
+			CycleEnd:         end.Format("2006-01-02"),
+			DaysLeft:         int(remainingDays + 0.999),
+			UsedBytes:        uint64(used),
+			UsedPercent:      used / float64(b.BudgetBytes) * 100,
+			DailyRate:        uint64(dailyRate),
+			ProjectedBytes:   uint64(projected),
+			ProjectedPercent: projected / float64(b.BudgetBytes) * 100,
+		}
+		for _, t := range budgetThresholds {
+			if st.UsedPercent >= float64(t) {
+				st.Level = t
+			}
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// billingCycle 计算 now 所在计费周期的起止时间（起始日超过当月天数时按月末计算）
+func billingCycle(now time.Time, startDay int) (time.Time, time.Time) {
+	start := cycleDay(now.Year(), now.Month(), startDay, now.Location())
+	if now.Before(start) {
+		start = cycleDay(now.Year(), now.Month()-1, startDay, now.Location())
+	}
+	end := cycleDay(start.Year(), start.Month()+1, startDay, now.Location())
+	return start, end
+}
+
+func cycleDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"time"
+
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+const (
+	netBaselineFile = "net_baseline.json"
+	netGapFile      = "net_gaps.jsonl"
+
+	// 部分网卡驱动使用 32 位计数器，超过 4GB 后从 0 重新计数
+	counterWrap32 = uint64(1) << 32
+	// 单次采样允许的最大速率（约 80 Gbps），超过视为计数异常
+	maxPlausibleRate = 10 * 1024 * 1024 * 1024
+	// 开机时间由 uptime 推算，可能有几秒误差
+	bootTimeTolerance = 10
+)
+
+// netCounter 单个网卡的累计计数器读数
+type netCounter struct {
+	Sent uint64 `json:"sent"`
+	Recv uint64 `json:"recv"`
+}
+
+// netBaseline 持久化的采样基准，用于跨程序重启计算增量
+type netBaseline struct {
+	BootTime uint64                `json:"boot_time"` // 系统开机时间（Unix 秒）
+	Counters map[string]netCounter `json:"counters"`  // 各网卡计数器
+	Time     string                `json:"time"`      // 采样时间（RFC 3339）
+}
+
+// counterDelta 计算两次计数器读数的差值
+//
+// 当前值小于上次值时：上次值接近 32 位上限则视为回绕，否则视为计数器被重置（重启、网卡重置等），
+// 此时返回 ok=false，由调用方记录数据缺口，而不是把无符号下溢的巨大数值写入历史。
+func counterDelta(prev, cur uint64, elapsed time.Duration) (uint64, bool) {
+	var delta uint64
+	switch {
+	case cur >= prev:
+		delta = cur - prev
+	case prev < counterWrap32 && prev > counterWrap32/4*3 && cur < counterWrap32/4:
+		delta = counterWrap32 - prev + cur
+	default:
+		return 0, false
+	}
+
+	seconds := elapsed.Seconds()
+	if seconds < 1 {
+		seconds = 1
+	}
+	if float64(delta)/seconds > maxPlausibleRate {
+		return 0, false
+	}
+	return delta, true
+}
+
+// currentBootTime 获取系统开机时间，失败返回 0
+func currentBootTime() uint64 {
+	boot, err := host.BootTime()
+	if err != nil {
+		return 0
+	}
+	return boot
+}
+
+// sameBoot 判断两次开机时间是否属于同一次开机
+func sameBoot(a, b uint64) bool {
+	if a > b {
+		a, b = b, a
+	}
+	return b-a <= bootTimeTolerance
+}
+
+func loadNetBaseline() (*netBaseline, error) {
+	var b netBaseline
+	if err := storage.Load(netBaselineFile, &b); err != nil {
+		return nil, err
+	}
+	// 旧版基准只有合计值，无法按网卡续算
+	if b.Time == "" || len(b.Counters) == 0 {
+		return nil, nil
+	}
+	return &b, nil
+}
+
+func saveNetBaseline(boot uint64, counters map[string]netCounter, now time.Time) {
+	_ = storage.Save(netBaselineFile, netBaseline{
+		BootTime: boot,
+		Counters: counters,
+		Time:     timeutil.Format(now),
+	})
+}
+
+// newNetGap 一段无法统计流量的时间（iface 为空表示全部网卡）
+func newNetGap(start, end time.Time, iface, reason string) model.NetTrafficGap {
+	return model.NetTrafficGap{
+		Start:  timeutil.Format(start),
+		End:    timeutil.Format(end),
+		Iface:  iface,
+		Reason: reason,
+	}
+}
+
+// loadNetGaps 读取最近 days 天内的数据缺口
+func loadNetGaps(days int) []model.NetTrafficGap {
+	gaps, err := storage.ReadRecords[model.NetTrafficGap](netGapFile)
+	if err != nil {
+		return nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	var recent []model.NetTrafficGap
+	for _, g := range gaps {
+		if end, err := timeutil.Parse(g.End); err == nil && end.After(cutoff) {
+			recent = append(recent, g)
+		}
+	}
+	return recent
+}
@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/timeutil"
+)
+
+func TestCounterDelta(t *testing.T) {
+	cases := []struct {
+		name      string
+		prev, cur uint64
+		elapsed   time.Duration
+		want      uint64
+		ok        bool
+	}{
+		{"increase", 1000, 5000, time.Minute, 4000, true},
+		{"unchanged", 1000, 1000, time.Minute, 0, true},
+		{"wrap32", counterWrap32 - 100, 400, time.Minute, 500, true},
+		{"reset", 5_000_000, 1000, time.Minute, 0, false},
+		{"reset near 32-bit limit but large cur", counterWrap32 - 100, counterWrap32 / 2, time.Minute, 0, false},
+		{"implausible rate", 0, 100 * maxPlausibleRate, time.Second, 0, false},
+		{"sub-second elapsed", 0, 1000, time.Millisecond, 1000, true},
+	}
+	for _, tc := range cases {
+		got, ok := counterDelta(tc.prev, tc.cur, tc.elapsed)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("%s: counterDelta(%d, %d) = %d, %v; want %d, %v", tc.name, tc.prev, tc.cur, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+// sumRecords 按网卡合计流量
+func sumRecords(records []model.NetTrafficRecord) map[string]netCounter {
+	sums := make(map[string]netCounter)
+	for _, r := range records {
+		c := sums[r.Iface]
+		c.Sent += r.Sent
+		c.Recv += r.Recv
+		sums[r.Iface] = c
+	}
+	return sums
+}
+
+func TestSpreadNetDelta(t *testing.T) {
+	since := time.Date(2024, 3, 8, 22, 30, 0, 0, time.UTC)
+	now := time.Date(2024, 3, 9, 1, 30, 0, 0, time.UTC)
+	slices := spreadNetDelta(since, now, 3000, 601)
+	if len(slices) != 4 {
+		t.Fatalf("slices = %d, want 4 (22:30-23, 23-00, 00-01, 01-01:30)", len(slices))
+	}
+	var sent, recv uint64
+	for i, sl := range slices {
+		sent += sl.sent
+		recv += sl.recv
+		if h := hourStart(sl.at); i > 0 && !h.After(hourStart(slices[i-1].at)) {
+			t.Errorf("slice %d at %v is not in a later hour", i, sl.at)
+		}
+	}
+	if sent != 3000 || recv != 601 {
+		t.Errorf("total = %d/%d, want 3000/601", sent, recv)
+	}
+	if slices[0].sent != 500 || slices[1].sent != 1000 {
+		t.Errorf("half hour / full hour = %d / %d, want 500 / 1000", slices[0].sent, slices[1].sent)
+	}
+}
+
+func TestNetDeltaRecords(t *testing.T) {
+	since := time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC)
+	prev := map[string]netCounter{"Wi-Fi": {Sent: 100, Recv: 200}, "Ethernet": {Sent: 5_000_000, Recv: 5_000_000}}
+	cur := map[string]netCounter{"Wi-Fi": {Sent: 160, Recv: 260}, "Ethernet": {Sent: 10, Recv: 10}, "New": {Sent: 9, Recv: 9}}
+
+	// 正常采样：一条记录，记在 now
+	now := since.Add(30 * time.Second)
+	records, gaps := netDeltaRecords(prev, cur, since, now, time.Minute)
+	if len(records) != 1 || records[0].Iface != "Wi-Fi" || records[0].Timestamp != timeutil.Format(now) || records[0].Sent != 60 {
+		t.Errorf("records = %+v", records)
+	}
+	if len(gaps) != 1 || gaps[0].Iface != "Ethernet" || gaps[0].Reason != "counter_reset" {
+		t.Errorf("gaps = %+v", gaps)
+	}
+
+	// 休眠两天后：分摊到各小时，总量不变，不会全部记在今天
+	now = since.Add(48 * time.Hour)
+	records, _ = netDeltaRecords(prev, cur, since, now, time.Minute)
+	if len(records) < 48 {
+		t.Fatalf("records = %d, want one per hour", len(records))
+	}
+	if got := sumRecords(records)["Wi-Fi"]; got.Sent != 60 || got.Recv != 60 {
+		t.Errorf("spread total = %+v, want 60/60", got)
+	}
+	first, _ := timeutil.Parse(records[0].Timestamp)
+	if !first.Before(since.Add(time.Hour)) {
+		t.Errorf("first record at %v, want within the first hour of the gap", first)
+	}
+}
+
+func TestResumeRecords(t *testing.T) {
+	baseTime := time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC)
+	bootAt := time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC)
+	now := bootAt.Add(2 * time.Hour)
+	base := &netBaseline{
+		BootTime: uint64(baseTime.Add(-24 * time.Hour).Unix()),
+		Counters: map[string]netCounter{"Wi-Fi": {Sent: 1000, Recv: 1000}},
+		Time:     timeutil.Format(baseTime),
+	}
+
+	t.Run("same boot", func(t *testing.T) {
+		cur := map[string]netCounter{"Wi-Fi": {Sent: 1000 + 7200, Recv: 1000}}
+		records, gaps := resumeRecords(base, base.BootTime+3, cur, baseTime.Add(2*time.Hour), time.Minute)
+		if len(gaps) != 0 {
+			t.Errorf("gaps = %+v", gaps)
+		}
+		if got := sumRecords(records)["Wi-Fi"]; got.Sent != 7200 {
+			t.Errorf("sent = %d, want 7200", got.Sent)
+		}
+		if len(records) != 2 {
+			t.Errorf("records = %d, want one per hour", len(records))
+		}
+	})
+
+	t.Run("reboot", func(t *testing.T) {
+		cur := map[string]netCounter{"Wi-Fi": {Sent: 3600, Recv: 7200}}
+		records, gaps := resumeRecords(base, uint64(bootAt.Unix()), cur, now, time.Minute)
+		if len(gaps) != 1 || gaps[0].Reason != "reboot" || gaps[0].End != timeutil.Format(bootAt) {
+			t.Errorf("gaps = %+v, want one reboot gap ending at boot", gaps)
+		}
+		// 开机以来的流量只分摊到开机之后，关机期间不计流量
+		for _, r := range records {
+			at, _ := timeutil.Parse(r.Timestamp)
+			if at.Before(bootAt) {
+				t.Errorf("record %v before boot", r)
+			}
+		}
+		if got := sumRecords(records)["Wi-Fi"]; got.Sent != 3600 || got.Recv != 7200 {
+			t.Errorf("total = %+v, want 3600/7200", got)
+		}
+	})
+
+	t.Run("counter reset without reboot", func(t *testing.T) {
+		cur := map[string]netCounter{"Wi-Fi": {Sent: 10, Recv: 10}}
+		records, gaps := resumeRecords(base, base.BootTime, cur, now, time.Minute)
+		if len(records) != 0 || len(gaps) != 1 || gaps[0].Reason != "counter_reset" {
+			t.Errorf("records = %+v, gaps = %+v", records, gaps)
+		}
+	})
+
+	t.Run("baseline from the future", func(t *testing.T) {
+		records, gaps := resumeRecords(base, base.BootTime, base.Counters, baseTime.Add(-time.Minute), time.Minute)
+		if records != nil || gaps != nil {
+			t.Errorf("records = %+v, gaps = %+v", records, gaps)
+		}
+	})
+}
@@ -1,173 +1,433 @@
 package monitor
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
 	"sort"
 	"sync"
 	"time"
 
 	"win-cleaner/internal/model"
-	"win-cleaner/pkg/datadir"
+	"win-cleaner/internal/settings"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
 
 	"github.com/shirou/gopsutil/v3/net"
 )
 
+// 流量历史分三级保存，均为只追加的 JSON Lines 日志：
+//   - net_minute.jsonl 每次采样每个网卡一条，默认保留 7 天
+//   - net_hourly.jsonl 按小时汇总，默认保留 1 年
+//   - net_daily.jsonl  按天汇总，永久保留
+//
+// 保留天数见设置（NetMinuteDays、NetHourlyDays），超出保留期的记录每小时汇总到下一级。汇总时只处理比下一级最后一条记录更新的时段，
+// 因此即使汇总过程中断，重新执行也不会重复计数。
+//
+// 小时按 UTC 划分（夏令时回拨时重复的本地小时不会被合并），天按记录所在时区的日期划分。
+const (
+	netMinuteFile = "net_minute.jsonl"
+	netHourlyFile = "net_hourly.jsonl"
+	netDailyFile  = "net_daily.jsonl"
+
+	hourKeyLayout = "2006-01-02T15Z"
+
+	rollupInterval = time.Hour
+)
+
 var (
 	nhMu           sync.Mutex
-	prevSampleSent uint64
-	prevSampleRecv uint64
+	prevCounters   map[string]netCounter // 上次采样时各网卡的计数器
 	prevSampleTime time.Time
 	sampleInited   bool
+	lastRollup     time.Time
 )
 
-func getNetHistoryPath() string {
-	return datadir.FilePath("net_history.json")
-}
+// RecordNetTrafficSample 按网卡采样并记录流量增量（记录全部网卡，统计时再按设置过滤）
+func RecordNetTrafficSample() {
+	nhMu.Lock()
+	defer nhMu.Unlock()
 
-func loadNetHistory() (*model.NetTrafficHistory, error) {
-	path := getNetHistoryPath()
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &model.NetTrafficHistory{}, nil
+	counters, err := net.IOCounters(true)
+	if err != nil || len(counters) == 0 {
+		return
+	}
+
+	current := make(map[string]netCounter, len(counters))
+	for _, c := range counters {
+		current[c.Name] = netCounter{Sent: c.BytesSent, Recv: c.BytesRecv}
+	}
+	now := time.Now()
+	boot := currentBootTime()
+
+	if !sampleInited {
+		sampleInited = true
+		// 从上次保存的基准继续统计，补上程序未运行期间的流量
+		if base, err := loadNetBaseline(); err == nil && base != nil {
+			resumeFromBaseline(base, boot, current, now)
 		}
-		return nil, err
+	} else {
+		recordNetDeltas(prevCounters, current, prevSampleTime, now)
 	}
-	var h model.NetTrafficHistory
-	if err := json.Unmarshal(data, &h); err != nil {
-		return &model.NetTrafficHistory{}, nil
+
+	prevCounters = current
+	prevSampleTime = now
+	saveNetBaseline(boot, current, now)
+
+	if now.Sub(lastRollup) >= rollupInterval {
+		if rollupNetHistory(now) == nil {
+			lastRollup = now
+		}
 	}
-	return &h, nil
 }
 
-func saveNetHistory(h *model.NetTrafficHistory) error {
-	path := getNetHistoryPath()
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
+// recordNetDeltas 计算各网卡相对上次读数的增量并写入分钟日志
+func recordNetDeltas(prev, cur map[string]netCounter, since, now time.Time) {
+	records, gaps := netDeltaRecords(prev, cur, since, now, netSpreadAfter())
+	for _, g := range gaps {
+		_ = storage.AppendRecords(netGapFile, g)
 	}
-	data, err := json.Marshal(h)
-	if err != nil {
-		return err
+	appendNetSamples(records)
+}
+
+// netSpreadAfter 两次读数间隔超过两个采样周期（休眠、程序未运行）时按时长分摊流量
+func netSpreadAfter() time.Duration {
+	return 2 * time.Duration(settings.Get().NetSampleInterval) * time.Second
+}
+
+// netDeltaRecords 计算各网卡相对上次读数的增量
+//
+// 间隔超过 spreadAfter 时，增量按时长比例分摊到所跨的各个小时，而不是全部记在 now，
+// 避免整个周末的流量都算到开机当天。计数器重置或增量不合理时返回缺口记录。
+func netDeltaRecords(prev, cur map[string]netCounter, since, now time.Time, spreadAfter time.Duration) ([]model.NetTrafficRecord, []model.NetTrafficGap) {
+	elapsed := now.Sub(since)
+	var records []model.NetTrafficRecord
+	var gaps []model.NetTrafficGap
+	for name, c := range cur {
+		p, ok := prev[name]
+		if !ok {
+			continue // 新出现的网卡，从本次读数开始统计
+		}
+		deltaSent, okSent := counterDelta(p.Sent, c.Sent, elapsed)
+		deltaRecv, okRecv := counterDelta(p.Recv, c.Recv, elapsed)
+		if !okSent || !okRecv {
+			reason := "counter_reset"
+			if c.Sent >= p.Sent && c.Recv >= p.Recv {
+				reason = "implausible"
+			}
+			gaps = append(gaps, newNetGap(since, now, name, reason))
+			continue
+		}
+		if deltaSent == 0 && deltaRecv == 0 {
+			continue
+		}
+		if elapsed <= spreadAfter {
+			records = append(records, newNetRecord(now, name, deltaSent, deltaRecv))
+			continue
+		}
+		for _, sl := range spreadNetDelta(since, now, deltaSent, deltaRecv) {
+			records = append(records, newNetRecord(sl.at, name, sl.sent, sl.recv))
+		}
 	}
-	return os.WriteFile(path, data, 0644)
+	return records, gaps
 }
 
-// RecordNetTrafficSample 采样并记录当前流量增量
-func RecordNetTrafficSample() {
-	nhMu.Lock()
-	defer nhMu.Unlock()
+// netSlice 分摊到某一小时内的流量，at 为该段时间的中点
+type netSlice struct {
+	at         time.Time
+	sent, recv uint64
+}
 
-	counters, err := net.IOCounters(false)
-	if err != nil || len(counters) == 0 {
-		return
+// spreadNetDelta 把 since 到 now 之间的流量按时长比例分到所跨的各个 UTC 小时（与小时汇总的划分一致），
+// 取整误差计入最后一段，总量不变
+func spreadNetDelta(since, now time.Time, sent, recv uint64) []netSlice {
+	total := now.Sub(since)
+	if total <= 0 {
+		return []netSlice{{at: now, sent: sent, recv: recv}}
+	}
+	var out []netSlice
+	var usedSent, usedRecv uint64
+	for start := since; start.Before(now); {
+		end := hourStart(start).Add(time.Hour)
+		if end.After(now) {
+			end = now
+		}
+		frac := float64(end.Sub(start)) / float64(total)
+		sl := netSlice{at: start.Add(end.Sub(start) / 2)}
+		if end.Equal(now) {
+			sl.sent, sl.recv = sent-usedSent, recv-usedRecv
+		} else {
+			sl.sent = min(uint64(float64(sent)*frac), sent-usedSent)
+			sl.recv = min(uint64(float64(recv)*frac), recv-usedRecv)
+		}
+		usedSent += sl.sent
+		usedRecv += sl.recv
+		if sl.sent != 0 || sl.recv != 0 {
+			out = append(out, sl)
+		}
+		start = end
 	}
+	return out
+}
 
-	currentSent := counters[0].BytesSent
-	currentRecv := counters[0].BytesRecv
-	now := time.Now()
+func newNetRecord(now time.Time, iface string, sent, recv uint64) model.NetTrafficRecord {
+	return model.NetTrafficRecord{
+		Timestamp: timeutil.Format(now),
+		Iface:     iface,
+		Sent:      sent,
+		Recv:      recv,
+	}
+}
 
-	if !sampleInited {
-		prevSampleSent = currentSent
-		prevSampleRecv = currentRecv
-		prevSampleTime = now
-		sampleInited = true
+// appendNetSamples 写入分钟级采样记录（按时间、网卡名排序，便于阅读）
+func appendNetSamples(records []model.NetTrafficRecord) {
+	if len(records) == 0 {
 		return
 	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Timestamp != records[j].Timestamp {
+			return timeutil.Before(records[i].Timestamp, records[j].Timestamp)
+		}
+		return records[i].Iface < records[j].Iface
+	})
+	_ = storage.AppendRecords(netMinuteFile, records...)
+}
 
-	deltaSent := currentSent - prevSampleSent
-	deltaRecv := currentRecv - prevSampleRecv
+// resumeFromBaseline 根据上次运行保存的基准补记程序未运行期间的流量
+//
+// 同一次开机：计数器差值即为期间流量；
+// 已重启：计数器从开机时归零，当前值即为开机以来的流量，而上次退出到关机之间的流量无法得知，记为缺口。
+func resumeFromBaseline(base *netBaseline, boot uint64, cur map[string]netCounter, now time.Time) {
+	records, gaps := resumeRecords(base, boot, cur, now, netSpreadAfter())
+	for _, g := range gaps {
+		_ = storage.AppendRecords(netGapFile, g)
+	}
+	appendNetSamples(records)
+}
 
-	prevSampleSent = currentSent
-	prevSampleRecv = currentRecv
-	prevSampleTime = now
+// resumeRecords 计算程序未运行期间的流量记录和缺口（不写文件）
+func resumeRecords(base *netBaseline, boot uint64, cur map[string]netCounter, now time.Time, spreadAfter time.Duration) ([]model.NetTrafficRecord, []model.NetTrafficGap) {
+	baseTime, err := timeutil.Parse(base.Time)
+	if err != nil || !baseTime.Before(now) {
+		return nil, nil
+	}
 
-	if deltaSent == 0 && deltaRecv == 0 {
-		return
+	if boot != 0 && base.BootTime != 0 && !sameBoot(base.BootTime, boot) {
+		bootAt := time.Unix(int64(boot), 0)
+		var gaps []model.NetTrafficGap
+		if bootAt.After(baseTime) {
+			gaps = append(gaps, newNetGap(baseTime, bootAt, "", "reboot"))
+		}
+		zero := make(map[string]netCounter, len(cur))
+		for name := range cur {
+			zero[name] = netCounter{}
+		}
+		records, deltaGaps := netDeltaRecords(zero, cur, bootAt, now, spreadAfter)
+		return records, append(gaps, deltaGaps...)
 	}
 
-	h, err := loadNetHistory()
-	if err != nil {
-		return
+	return netDeltaRecords(base.Counters, cur, baseTime, now, spreadAfter)
+}
+
+// rollupNetHistory 把超出保留期的分钟记录汇总为小时，小时记录汇总为天
+func rollupNetHistory(now time.Time) error {
+	s := settings.Get()
+	minuteCutoff := now.AddDate(0, 0, -s.NetMinuteDays).UTC().Format(hourKeyLayout)
+	if err := rollupTier(netMinuteFile, netHourlyFile, minuteCutoff, hourKey, hourStart); err != nil {
+		return err
 	}
+	hourlyCutoff := timeutil.Day(now.AddDate(0, 0, -s.NetHourlyDays))
+	return rollupTier(netHourlyFile, netDailyFile, hourlyCutoff, dayKey, dayStart)
+}
+
+// recordTime 解析记录时间，保留记录所在时区
+func recordTime(r model.NetTrafficRecord) (time.Time, bool) {
+	t, err := timeutil.Parse(r.Timestamp)
+	return t, err == nil
+}
 
-	record := model.NetTrafficRecord{
-		Timestamp: now.Format("2006-01-02 15:04"),
-		Date:      now.Format("2006-01-02"),
-		Sent:      deltaSent,
-		Recv:      deltaRecv,
+// hourKey 记录所属小时（UTC）"YYYY-MM-DDTHHZ"，时间无法解析时返回空
+func hourKey(r model.NetTrafficRecord) string {
+	t, ok := recordTime(r)
+	if !ok {
+		return ""
 	}
+	return t.UTC().Format(hourKeyLayout)
+}
 
-	// 合并同一分钟的记录
-	if len(h.Records) > 0 {
-		last := &h.Records[len(h.Records)-1]
-		if last.Timestamp == record.Timestamp {
-			last.Sent += record.Sent
-			last.Recv += record.Recv
-			_ = saveNetHistory(h)
-			return
-		}
+// dayKey 记录所在时区的日期 "YYYY-MM-DD"，时间无法解析时返回空
+func dayKey(r model.NetTrafficRecord) string {
+	t, ok := recordTime(r)
+	if !ok {
+		return ""
+	}
+	return timeutil.Day(t)
+}
+
+func hourStart(t time.Time) time.Time {
+	return t.Truncate(time.Hour)
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// rollupTier 将 src 中时段早于 cutoff 的记录按 keyFn 汇总后追加到 dst，并从 src 中移除
+//
+// 汇总记录的时间戳为时段起点（startFn），保留原记录的时区偏移。
+func rollupTier(src, dst, cutoff string, keyFn func(model.NetTrafficRecord) string, startFn func(time.Time) time.Time) error {
+	dstRecords, err := storage.ReadRecords[model.NetTrafficRecord](dst)
+	if err != nil {
+		return err
+	}
+	lastKey := ""
+	if len(dstRecords) > 0 {
+		lastKey = keyFn(dstRecords[len(dstRecords)-1])
 	}
 
-	h.Records = append(h.Records, record)
+	return storage.UpdateRecords(src, func(records []model.NetTrafficRecord) ([]model.NetTrafficRecord, error) {
+		var kept []model.NetTrafficRecord
+		var keys []string
+		rolled := make(map[string]*model.NetTrafficRecord)
+		for _, r := range records {
+			key := keyFn(r)
+			if key == "" {
+				continue // 时间无法解析的记录无法归入任何时段，直接丢弃
+			}
+			if key >= cutoff {
+				kept = append(kept, r)
+				continue
+			}
+			if key <= lastKey {
+				continue // 已汇总过（上次汇总在清理前中断）
+			}
+			// 按 时段 + 网卡 汇总，时段在前保证排序后按时间追加
+			groupKey := key + "|" + r.Iface
+			if agg, ok := rolled[groupKey]; ok {
+				agg.Sent += r.Sent
+				agg.Recv += r.Recv
+				continue
+			}
+			t, _ := recordTime(r)
+			rolled[groupKey] = &model.NetTrafficRecord{Timestamp: timeutil.Format(startFn(t)), Iface: r.Iface, Sent: r.Sent, Recv: r.Recv}
+			keys = append(keys, groupKey)
+		}
 
-	// 只保留最近 90 天的记录
-	cutoff := now.AddDate(0, 0, -90).Format("2006-01-02")
-	filtered := h.Records[:0]
-	for _, r := range h.Records {
-		if r.Date >= cutoff {
-			filtered = append(filtered, r)
+		sort.Strings(keys)
+		out := make([]model.NetTrafficRecord, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, *rolled[k])
 		}
+		if err := storage.AppendRecords(dst, out...); err != nil {
+			return nil, err
+		}
+		return kept, nil
+	})
+}
+
+// loadNetDayTotals 合并三级日志，得到每天的流量（每个时段只取一个级别，避免重复计数）
+func loadNetDayTotals() ([]model.NetTrafficRecord, error) {
+	daily, err := storage.ReadRecords[model.NetTrafficRecord](netDailyFile)
+	if err != nil {
+		return nil, err
+	}
+	hourly, err := storage.ReadRecords[model.NetTrafficRecord](netHourlyFile)
+	if err != nil {
+		return nil, err
+	}
+	minute, err := storage.ReadRecords[model.NetTrafficRecord](netMinuteFile)
+	if err != nil {
+		return nil, err
 	}
-	h.Records = filtered
 
-	_ = saveNetHistory(h)
+	lastDay, lastHour := "", ""
+	if len(daily) > 0 {
+		lastDay = dayKey(daily[len(daily)-1])
+	}
+	if len(hourly) > 0 {
+		lastHour = hourKey(hourly[len(hourly)-1])
+	}
+
+	records := append([]model.NetTrafficRecord{}, daily...)
+	for _, r := range hourly {
+		if dayKey(r) > lastDay {
+			records = append(records, r)
+		}
+	}
+	for _, r := range minute {
+		if dayKey(r) > lastDay && hourKey(r) > lastHour {
+			records = append(records, r)
+		}
+	}
+	return records, nil
 }
 
-// GetNetTrafficStats 获取流量历史统计
+// GetNetTrafficStats 获取流量历史统计（总计只包含计入统计的网卡，另按网卡分别统计）
 func GetNetTrafficStats() (*model.NetTrafficStats, error) {
 	nhMu.Lock()
 	defer nhMu.Unlock()
 
-	h, err := loadNetHistory()
+	records, err := loadNetDayTotals()
 	if err != nil {
 		return nil, err
 	}
 
-	stats := &model.NetTrafficStats{}
-
-	// 按天
-	dailyMap := make(map[string]*model.NetDailyStat)
-	for _, r := range h.Records {
-		if d, ok := dailyMap[r.Date]; ok {
-			d.Sent += r.Sent
-			d.Recv += r.Recv
-		} else {
-			dailyMap[r.Date] = &model.NetDailyStat{
-				Date: r.Date, Sent: r.Sent, Recv: r.Recv,
-			}
+	cfg := loadIfaceConfig()
+	var included []model.NetTrafficRecord
+	byIface := make(map[string][]model.NetTrafficRecord)
+	for _, r := range records {
+		byIface[r.Iface] = append(byIface[r.Iface], r)
+		if ifaceIncluded(r.Iface, cfg) {
+			included = append(included, r)
 		}
-		stats.TotalSent += r.Sent
-		stats.TotalRecv += r.Recv
 	}
 
-	// 近 30 天
-	cutoff30 := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
-	for _, d := range dailyMap {
-		if d.Date >= cutoff30 {
-			stats.DailyStats = append(stats.DailyStats, *d)
+	stats := &model.NetTrafficStats{
+		Gaps: loadNetGaps(30),
+	}
+	stats.DailyStats, stats.MonthlyStats, stats.YearlyStats, stats.TotalSent, stats.TotalRecv = aggregateNetRecords(included)
+
+	for name, recs := range byIface {
+		is := model.NetInterfaceStats{
+			Name:     name,
+			Virtual:  name != "" && isVirtualIface(name),
+			Included: ifaceIncluded(name, cfg),
 		}
+		is.DailyStats, is.MonthlyStats, is.YearlyStats, is.TotalSent, is.TotalRecv = aggregateNetRecords(recs)
+		stats.Interfaces = append(stats.Interfaces, is)
 	}
-	sort.Slice(stats.DailyStats, func(i, j int) bool {
-		return stats.DailyStats[i].Date < stats.DailyStats[j].Date
+	sort.Slice(stats.Interfaces, func(i, j int) bool {
+		a, b := stats.Interfaces[i], stats.Interfaces[j]
+		return a.TotalSent+a.TotalRecv > b.TotalSent+b.TotalRecv
 	})
 
-	// 按月
+	return stats, nil
+}
+
+// aggregateNetRecords 按天（近 30 天）、按月、按年汇总流量，日期取记录所在时区
+func aggregateNetRecords(records []model.NetTrafficRecord) (daily []model.NetDailyStat, monthly []model.NetMonthlyStat, yearly []model.NetYearlyStat, totalSent, totalRecv uint64) {
+	dailyMap := make(map[string]*model.NetDailyStat)
 	monthlyMap := make(map[string]*model.NetMonthlyStat)
-	for _, r := range h.Records {
-		month := r.Date[:7]
+	yearlyMap := make(map[string]*model.NetYearlyStat)
+	for _, r := range records {
+		totalSent += r.Sent
+		totalRecv += r.Recv
+
+		t, ok := recordTime(r)
+		if !ok {
+			continue
+		}
+
+		// 按天
+		day := timeutil.Day(t)
+		if d, ok := dailyMap[day]; ok {
+			d.Sent += r.Sent
+			d.Recv += r.Recv
+		} else {
+			dailyMap[day] = &model.NetDailyStat{
+				Date: day, Sent: r.Sent, Recv: r.Recv,
+			}
+		}
+
+		// 按月
+		month := timeutil.Month(t)
 		if m, ok := monthlyMap[month]; ok {
 			m.Sent += r.Sent
 			m.Recv += r.Recv
@@ -176,18 +436,9 @@ func GetNetTrafficStats() (*model.NetTrafficStats, error) {
 				Month: month, Sent: r.Sent, Recv: r.Recv,
 			}
 		}
-	}
-	for _, m := range monthlyMap {
-		stats.MonthlyStats = append(stats.MonthlyStats, *m)
-	}
-	sort.Slice(stats.MonthlyStats, func(i, j int) bool {
-		return stats.MonthlyStats[i].Month < stats.MonthlyStats[j].Month
-	})
 
-	// 按年
-	yearlyMap := make(map[string]*model.NetYearlyStat)
-	for _, r := range h.Records {
-		year := r.Date[:4]
+		// 按年
+		year := timeutil.Year(t)
 		if y, ok := yearlyMap[year]; ok {
 			y.Sent += r.Sent
 			y.Recv += r.Recv
@@ -197,12 +448,31 @@ func GetNetTrafficStats() (*model.NetTrafficStats, error) {
 			}
 		}
 	}
+
+	// 近 30 天
+	cutoff30 := timeutil.Day(time.Now().AddDate(0, 0, -30))
+	for _, d := range dailyMap {
+		if d.Date >= cutoff30 {
+			daily = append(daily, *d)
+		}
+	}
+	sort.Slice(daily, func(i, j int) bool {
+		return daily[i].Date < daily[j].Date
+	})
+
+	for _, m := range monthlyMap {
+		monthly = append(monthly, *m)
+	}
+	sort.Slice(monthly, func(i, j int) bool {
+		return monthly[i].Month < monthly[j].Month
+	})
+
 	for _, y := range yearlyMap {
-		stats.YearlyStats = append(stats.YearlyStats, *y)
+		yearly = append(yearly, *y)
 	}
-	sort.Slice(stats.YearlyStats, func(i, j int) bool {
-		return stats.YearlyStats[i].Year < stats.YearlyStats[j].Year
+	sort.Slice(yearly, func(i, j int) bool {
+		return yearly[i].Year < yearly[j].Year
 	})
 
-	return stats, nil
+	return daily, monthly, yearly, totalSent, totalRecv
 }
@@ -0,0 +1,159 @@
+package monitor
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/storage"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+const netIfaceFile = "net_interfaces.json"
+
+// netIfaceConfig 用户对网卡的包含/排除设置（优先于默认的虚拟网卡识别）
+type netIfaceConfig struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+var (
+	ifaceCfg       netIfaceConfig
+	ifaceCfgLoaded bool
+	ifaceCfgMu     sync.Mutex
+)
+
+// 虚拟网卡名称关键词（Windows 友好名称）
+var virtualIfaceKeywords = []string{
+	"loopback", "pseudo-interface", "vethernet", "hyper-v", "virtualbox", "vmware",
+	"docker", "wsl", "isatap", "teredo", "6to4", "vpn", "tap-", "wireguard",
+	"tailscale", "zerotier",
+}
+
+// 虚拟网卡名称前缀（Linux 网卡名）
+var virtualIfacePrefixes = []string{
+	"veth", "docker", "br-", "virbr", "tun", "tap", "wg", "vmnet", "vboxnet", "tailscale", "zt",
+}
+
+// isVirtualIface 根据名称判断是否为虚拟网卡
+func isVirtualIface(name string) bool {
+	lower := strings.ToLower(name)
+	if lower == "lo" {
+		return true
+	}
+	for _, p := range virtualIfacePrefixes {
+		if strings.HasPrefix(lower, p) {
+			return true
+		}
+	}
+	for _, kw := range virtualIfaceKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadIfaceConfig 返回配置副本，调用方在锁外读取时不会与修改共用底层数组
+func loadIfaceConfig() netIfaceConfig {
+	ifaceCfgMu.Lock()
+	defer ifaceCfgMu.Unlock()
+	if !ifaceCfgLoaded {
+		_ = storage.Load(netIfaceFile, &ifaceCfg)
+		ifaceCfgLoaded = true
+	}
+	return netIfaceConfig{
+		Include: slices.Clone(ifaceCfg.Include),
+		Exclude: slices.Clone(ifaceCfg.Exclude),
+	}
+}
+
+// ifaceIncluded 网卡是否计入总流量：用户设置优先，否则排除虚拟网卡
+func ifaceIncluded(name string, cfg netIfaceConfig) bool {
+	if name == "" {
+		return true // 旧版未区分网卡的记录
+	}
+	for _, n := range cfg.Exclude {
+		if n == name {
+			return false
+		}
+	}
+	for _, n := range cfg.Include {
+		if n == name {
+			return true
+		}
+	}
+	return !isVirtualIface(name)
+}
+
+// GetNetInterfaces 列出当前网卡及其是否计入统计
+func GetNetInterfaces() ([]model.NetInterfaceInfo, error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil, err
+	}
+	cfg := loadIfaceConfig()
+
+	list := make([]model.NetInterfaceInfo, 0, len(counters))
+	for _, c := range counters {
+		list = append(list, model.NetInterfaceInfo{
+			Name:      c.Name,
+			Virtual:   isVirtualIface(c.Name),
+			Included:  ifaceIncluded(c.Name, cfg),
+			BytesSent: c.BytesSent,
+			BytesRecv: c.BytesRecv,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// SetNetInterfaceIncluded 设置网卡是否计入流量统计（对历史数据同样生效）
+func SetNetInterfaceIncluded(name string, included bool) error {
+	ifaceCfgMu.Lock()
+	defer ifaceCfgMu.Unlock()
+
+	return storage.Update(netIfaceFile, &ifaceCfg, func() error {
+		ifaceCfg.Include = removeString(ifaceCfg.Include, name)
+		ifaceCfg.Exclude = removeString(ifaceCfg.Exclude, name)
+		if included {
+			ifaceCfg.Include = append(ifaceCfg.Include, name)
+		} else {
+			ifaceCfg.Exclude = append(ifaceCfg.Exclude, name)
+		}
+		ifaceCfgLoaded = true
+		return nil
+	})
+}
+
+// removeString 返回去掉 s 后的新切片，不修改原切片
+func removeString(list []string, s string) []string {
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// includedCounters 读取计入统计的各网卡计数器（按网卡名）
+func includedCounters() (map[string]netCounter, error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil, err
+	}
+	cfg := loadIfaceConfig()
+	result := make(map[string]netCounter, len(counters))
+	for _, c := range counters {
+		if ifaceIncluded(c.Name, cfg) {
+			result[c.Name] = netCounter{Sent: c.BytesSent, Recv: c.BytesRecv}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,319 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+)
+
+// ExportNetRecords 导出三级流量日志
+func ExportNetRecords() (*model.NetTrafficTiers, error) {
+	nhMu.Lock()
+	defer nhMu.Unlock()
+	return readNetTiers()
+}
+
+// StageNetImport 读取本地流量日志并计算导入结果，不修改文件；返回的 commit 执行写入
+//
+// replace 为 true 时用导入的三级日志替换本地日志。
+// 合并时，落在本地已汇总时段内的记录先汇总到对应级别（否则会被汇总水位线忽略），
+// 同一级别中 时间 + 网卡 已存在的记录视为重复跳过。commit 按写入时的日志重新合并。
+func StageNetImport(tiers model.NetTrafficTiers, replace bool) (added, skipped int, commit func() error, err error) {
+	nhMu.Lock()
+	_, added, skipped, err = planNetImport(tiers, replace)
+	nhMu.Unlock()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	commit = func() error {
+		nhMu.Lock()
+		defer nhMu.Unlock()
+		writes, _, _, err := planNetImport(tiers, replace)
+		if err != nil {
+			return err
+		}
+		for _, w := range writes {
+			if err := storage.RewriteRecords(w.name, w.records); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return added, skipped, commit, nil
+}
+
+// netTierWrite 导入后某一级日志的完整内容
+type netTierWrite struct {
+	name    string
+	records []model.NetTrafficRecord
+}
+
+// planNetImport 计算导入后需要改写的各级日志（调用方持有 nhMu）
+func planNetImport(tiers model.NetTrafficTiers, replace bool) (writes []netTierWrite, added, skipped int, err error) {
+	if replace {
+		for _, t := range []netTierWrite{
+			{netMinuteFile, tiers.Minute},
+			{netHourlyFile, tiers.Hourly},
+			{netDailyFile, tiers.Daily},
+		} {
+			records := append([]model.NetTrafficRecord{}, t.records...)
+			sortNetRecords(records)
+			writes = append(writes, netTierWrite{t.name, records})
+			added += len(records)
+		}
+		return writes, added, 0, nil
+	}
+
+	local, err := readNetTiers()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	lastDay, lastHour := "", ""
+	if len(local.Daily) > 0 {
+		lastDay = dayKey(local.Daily[len(local.Daily)-1])
+	}
+	if len(local.Hourly) > 0 {
+		lastHour = hourKey(local.Hourly[len(local.Hourly)-1])
+	}
+
+	// 按本地水位线把导入记录放入能容纳它的最细级别：
+	// 早于本地已汇总时段的记录汇总到对应级别，其余原样写入分钟日志，之后随正常汇总逐级合并
+	var minute, hourly, daily []model.NetTrafficRecord
+	for _, tier := range [][]model.NetTrafficRecord{tiers.Minute, tiers.Hourly, tiers.Daily} {
+		for _, r := range tier {
+			day := dayKey(r)
+			switch {
+			case day == "":
+				continue
+			case day <= lastDay:
+				daily = append(daily, r)
+			case hourKey(r) <= lastHour:
+				hourly = append(hourly, r)
+			default:
+				minute = append(minute, r)
+			}
+		}
+	}
+
+	for _, t := range []struct {
+		name     string
+		local    []model.NetTrafficRecord
+		incoming []model.NetTrafficRecord
+	}{
+		{netMinuteFile, local.Minute, minute},
+		{netHourlyFile, local.Hourly, aggregateNetPeriod(hourly, hourStart)},
+		{netDailyFile, local.Daily, aggregateNetPeriod(daily, dayStart)},
+	} {
+		merged, a, s := mergeNetTier(t.local, t.incoming)
+		added += a
+		skipped += s
+		if a > 0 {
+			writes = append(writes, netTierWrite{t.name, merged})
+		}
+	}
+	return writes, added, skipped, nil
+}
+
+// NetDailyTotals 每天每个网卡的流量合计（时间戳为当天零点），用于导出报表
+func NetDailyTotals() ([]model.NetTrafficRecord, error) {
+	nhMu.Lock()
+	records, err := loadNetDayTotals()
+	nhMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return aggregateNetPeriod(records, dayStart), nil
+}
+
+func readNetTiers() (*model.NetTrafficTiers, error) {
+	var tiers model.NetTrafficTiers
+	var err error
+	if tiers.Minute, err = storage.ReadRecords[model.NetTrafficRecord](netMinuteFile); err != nil {
+		return nil, err
+	}
+	if tiers.Hourly, err = storage.ReadRecords[model.NetTrafficRecord](netHourlyFile); err != nil {
+		return nil, err
+	}
+	if tiers.Daily, err = storage.ReadRecords[model.NetTrafficRecord](netDailyFile); err != nil {
+		return nil, err
+	}
+	return &tiers, nil
+}
+
+// aggregateNetPeriod 按 时段起点 + 网卡 汇总记录，结果按时间排序
+func aggregateNetPeriod(records []model.NetTrafficRecord, startFn func(time.Time) time.Time) []model.NetTrafficRecord {
+	var keys []string
+	groups := make(map[string]*model.NetTrafficRecord)
+	for _, r := range records {
+		t, ok := recordTime(r)
+		if !ok {
+			continue
+		}
+		start := startFn(t)
+		key := start.UTC().Format(time.RFC3339) + "|" + r.Iface
+		if g, ok := groups[key]; ok {
+			g.Sent += r.Sent
+			g.Recv += r.Recv
+			continue
+		}
+		groups[key] = &model.NetTrafficRecord{Timestamp: timeutil.Format(start), Iface: r.Iface, Sent: r.Sent, Recv: r.Recv}
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	out := make([]model.NetTrafficRecord, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, *groups[k])
+	}
+	return out
+}
+
+// mergeNetTier 把记录合并进一个级别的日志，时间戳、网卡和流量都相同的视为重复
+//
+// 网卡名（如 "Wi-Fi"）在不同电脑上常常相同，只比较时间和网卡会把另一台电脑同一时段的流量当作重复丢掉。
+func mergeNetTier(local, incoming []model.NetTrafficRecord) (merged []model.NetTrafficRecord, added, skipped int) {
+	seen := make(map[string]bool, len(local))
+	for _, r := range local {
+		seen[netRecordKey(r)] = true
+	}
+	merged = append([]model.NetTrafficRecord{}, local...)
+	for _, r := range incoming {
+		key := netRecordKey(r)
+		if seen[key] {
+			skipped++
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, r)
+		added++
+	}
+	sortNetRecords(merged)
+	return merged, added, skipped
+}
+
+// netRecordKey 按实际时刻比较，同一时刻不同时区偏移的写法视为同一条
+func netRecordKey(r model.NetTrafficRecord) string {
+	stamp := r.Timestamp
+	if t, ok := recordTime(r); ok {
+		stamp = t.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%s|%s|%d|%d", stamp, r.Iface, r.Sent, r.Recv)
+}
+
+// sortNetRecords 按时间、网卡名排序，保证日志最后一条是最新时段（汇总水位线依赖这一点）
+func sortNetRecords(records []model.NetTrafficRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		ta, okA := recordTime(a)
+		tb, okB := recordTime(b)
+		if okA && okB && !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return a.Iface < b.Iface
+	})
+}
+
+// ExportNetConfig 导出网卡统计设置与流量套餐
+func ExportNetConfig() (*model.NetConfig, error) {
+	iface := loadIfaceConfig()
+	var budgets netBudgetConfig
+	if err := storage.Load(netBudgetFile, &budgets); err != nil {
+		return nil, err
+	}
+	return &model.NetConfig{
+		Include: iface.Include,
+		Exclude: iface.Exclude,
+		Budgets: budgets.Budgets,
+	}, nil
+}
+
+// StageNetConfigImport 读取本地网卡设置与流量套餐并计算导入结果，不修改文件；返回的 commit 执行写入
+//
+// replace 为 true 时覆盖本地设置；合并时只导入本地尚未设置的网卡和套餐。
+func StageNetConfigImport(cfg model.NetConfig, replace bool) (added, skipped int, commit func() error, err error) {
+	iface := loadIfaceConfig()
+	var budgets netBudgetConfig
+	if err := storage.Load(netBudgetFile, &budgets); err != nil {
+		return 0, 0, nil, err
+	}
+	a1, s1 := mergeIfaceConfig(&iface, cfg, replace)
+	a2, s2 := mergeBudgets(&budgets, cfg.Budgets, replace)
+
+	commit = func() error {
+		ifaceCfgMu.Lock()
+		var iface netIfaceConfig
+		err := storage.Update(netIfaceFile, &iface, func() error {
+			mergeIfaceConfig(&iface, cfg, replace)
+			return nil
+		})
+		if err == nil {
+			ifaceCfg = iface
+			ifaceCfgLoaded = true
+		}
+		ifaceCfgMu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		var budgets netBudgetConfig
+		return storage.Update(netBudgetFile, &budgets, func() error {
+			mergeBudgets(&budgets, cfg.Budgets, replace)
+			return nil
+		})
+	}
+	return a1 + a2, s1 + s2, commit, nil
+}
+
+// mergeIfaceConfig 把导入的网卡包含/排除设置并入 c，本地已设置的网卡保持不变
+func mergeIfaceConfig(c *netIfaceConfig, cfg model.NetConfig, replace bool) (added, skipped int) {
+	if replace {
+		*c = netIfaceConfig{}
+	}
+	known := make(map[string]bool)
+	for _, n := range append(append([]string{}, c.Include...), c.Exclude...) {
+		known[n] = true
+	}
+	for _, list := range []struct {
+		src []string
+		dst *[]string
+	}{
+		{cfg.Include, &c.Include},
+		{cfg.Exclude, &c.Exclude},
+	} {
+		for _, n := range list.src {
+			if known[n] {
+				skipped++
+				continue
+			}
+			known[n] = true
+			*list.dst = append(*list.dst, n)
+			added++
+		}
+	}
+	return added, skipped
+}
+
+// mergeBudgets 把导入的流量套餐并入 b，本地已有套餐的网卡保持不变
+func mergeBudgets(b *netBudgetConfig, list []model.NetBudget, replace bool) (added, skipped int) {
+	if replace {
+		*b = netBudgetConfig{}
+	}
+	known := make(map[string]bool)
+	for _, nb := range b.Budgets {
+		known[nb.Iface] = true
+	}
+	for _, nb := range list {
+		if known[nb.Iface] {
+			skipped++
+			continue
+		}
+		known[nb.Iface] = true
+		b.Budgets = append(b.Budgets, nb)
+		added++
+	}
+	return added, skipped
+}
@@ -0,0 +1,27 @@
+package monitor
+
+import (
+	"testing"
+
+	"win-cleaner/internal/model"
+)
+
+func TestMergeNetTier(t *testing.T) {
+	local := []model.NetTrafficRecord{
+		{Timestamp: "2024-03-05T14:00:00Z", Iface: "Wi-Fi", Sent: 100, Recv: 200},
+	}
+	incoming := []model.NetTrafficRecord{
+		// 同一条记录，时区写法不同
+		{Timestamp: "2024-03-05T22:00:00+08:00", Iface: "Wi-Fi", Sent: 100, Recv: 200},
+		// 另一台电脑同一时段、同名网卡的流量
+		{Timestamp: "2024-03-05T14:00:00Z", Iface: "Wi-Fi", Sent: 7, Recv: 9},
+		{Timestamp: "2024-03-05T13:00:00Z", Iface: "Ethernet", Sent: 1, Recv: 1},
+	}
+	merged, added, skipped := mergeNetTier(local, incoming)
+	if added != 2 || skipped != 1 {
+		t.Errorf("added/skipped = %d/%d, want 2/1", added, skipped)
+	}
+	if len(merged) != 3 || merged[0].Iface != "Ethernet" {
+		t.Errorf("merged = %+v, want 3 records sorted by time", merged)
+	}
+}
@@ -13,14 +13,12 @@ import (
 
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
 )
 
 var (
-	lastNetSent uint64
-	lastNetRecv uint64
-	lastNetTime time.Time
-	netMu       sync.Mutex
+	lastNetCounters map[string]netCounter
+	lastNetTime     time.Time
+	netMu           sync.Mutex
 )
 
 // GetRealtimeStats 获取实时状态（CPU、内存、网速）
@@ -50,30 +48,40 @@ func GetRealtimeStats() (*model.RealtimeStats, error) {
 }
 
 // getNetSpeed 计算网络速率
+//
+// 按网卡分别求增量再相加：新出现或刚被计入的网卡没有上次读数，本次不计，
+// 避免把它的累计值当成瞬时流量。
 func getNetSpeed() (uint64, uint64) {
 	netMu.Lock()
 	defer netMu.Unlock()
 
-	counters, err := net.IOCounters(false)
-	if err != nil || len(counters) == 0 {
+	current, err := includedCounters()
+	if err != nil {
 		return 0, 0
 	}
-
-	currentSent := counters[0].BytesSent
-	currentRecv := counters[0].BytesRecv
 	now := time.Now()
 
 	var upSpeed, downSpeed uint64
-	if !lastNetTime.IsZero() {
-		elapsed := now.Sub(lastNetTime).Seconds()
-		if elapsed > 0 {
-			upSpeed = uint64(float64(currentSent-lastNetSent) / elapsed)
-			downSpeed = uint64(float64(currentRecv-lastNetRecv) / elapsed)
+	if elapsed := now.Sub(lastNetTime); !lastNetTime.IsZero() && elapsed > 0 {
+		var deltaSent, deltaRecv uint64
+		for name, cur := range current {
+			prev, ok := lastNetCounters[name]
+			if !ok {
+				continue
+			}
+			ds, okSent := counterDelta(prev.Sent, cur.Sent, elapsed)
+			dr, okRecv := counterDelta(prev.Recv, cur.Recv, elapsed)
+			// 计数器重置或回绕异常的网卡本次按 0 处理，下次采样恢复正常
+			if okSent && okRecv {
+				deltaSent += ds
+				deltaRecv += dr
+			}
 		}
+		upSpeed = uint64(float64(deltaSent) / elapsed.Seconds())
+		downSpeed = uint64(float64(deltaRecv) / elapsed.Seconds())
 	}
 
-	lastNetSent = currentSent
-	lastNetRecv = currentRecv
+	lastNetCounters = current
 	lastNetTime = now
 
 	return upSpeed, downSpeed
@@ -81,15 +89,15 @@ func getNetSpeed() (uint64, uint64) {
 
 // GetNetTraffic 获取网络流量详情（总览 + 按应用）
 func GetNetTraffic() (*model.NetTrafficResult, error) {
-	counters, err := net.IOCounters(false)
+	counters, err := includedCounters()
 	if err != nil {
 		return nil, err
 	}
 
-	overview := model.NetTrafficInfo{}
-	if len(counters) > 0 {
-		overview.TotalSent = counters[0].BytesSent
-		overview.TotalRecv = counters[0].BytesRecv
+	var overview model.NetTrafficInfo
+	for _, c := range counters {
+		overview.TotalSent += c.Sent
+		overview.TotalRecv += c.Recv
 	}
 	overview.UpSpeed, overview.DownSpeed = getNetSpeed()
 
@@ -1,120 +1,173 @@
 package monitor
 
 import (
+	"net"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"win-cleaner/internal/model"
-	"win-cleaner/pkg/winapi"
+	"win-cleaner/pkg/timeutil"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
 )
 
-func GetPortListSimple(port uint16) ([]model.PortInfo, error) {
-	psScript := `$conns = Get-NetTCPConnection -LocalPort ` + strconv.FormatUint(uint64(port), 10) + ` -ErrorAction SilentlyContinue
-foreach ($c in $conns) {
-  $proc = Get-Process -Id $c.OwningProcess -ErrorAction SilentlyContinue
-  $name = if ($proc) { $proc.ProcessName } else { "Unknown" }
-  "$($c.LocalAddress):$($c.LocalPort)|$($c.RemoteAddress):$($c.RemotePort)|$($c.State)|$($c.OwningProcess)|$name"
-}`
+// TCP 监听状态（gopsutil 在 Windows 和 Linux 上使用相同的状态名）
+const tcpStateListen = "LISTEN"
 
-	output, err := winapi.HiddenCmd("powershell", "-NoProfile", "-Command", psScript).Output()
+// ListConnections 列出本机 TCP/UDP（IPv4/IPv6）连接及所属进程，按 filter 过滤
+func ListConnections(filter model.PortFilter) (*model.PortScanResult, error) {
+	conns, err := psnet.Connections("inet")
 	if err != nil {
 		return nil, err
 	}
 
-	var result []model.PortInfo
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+	result := &model.PortScanResult{
+		Ports:       []model.PortInfo{},
+		StateCounts: make(map[string]int),
+		ScanTime:    timeutil.Now(),
+	}
+	names := make(map[int32]string)
+	for _, c := range conns {
+		p := toPortInfo(c)
+		if !matchPortFilter(p, filter) {
 			continue
 		}
-
-		parts := strings.SplitN(line, "|", 5)
-		if len(parts) < 5 {
-			continue
+		p.ProcessName = processName(names, p.PID)
+		result.Ports = append(result.Ports, p)
+
+		if p.Proto == "tcp" {
+			result.TCPCount++
+			result.StateCounts[p.Status]++
+		} else {
+			result.UDPCount++
 		}
+		if p.Listening {
+			result.ListenCount++
+		}
+	}
+	result.Count = len(result.Ports)
+	sortPorts(result.Ports)
+	return result, nil
+}
 
-		localAddr := strings.TrimSpace(parts[0])
-		state := strings.TrimSpace(parts[2])
-		pid, _ := strconv.ParseInt(strings.TrimSpace(parts[3]), 10, 64)
-		procName := strings.TrimSpace(parts[4])
-
-		result = append(result, model.PortInfo{
-			ListenAddr:  localAddr,
-			Port:        port,
-			Proto:       "tcp",
-			PID:         int32(pid),
-			ProcessName: procName,
-			Status:      state,
-		})
+// toPortInfo 转换 gopsutil 的连接记录
+func toPortInfo(c psnet.ConnectionStat) model.PortInfo {
+	p := model.PortInfo{
+		LocalIP:    c.Laddr.IP,
+		Port:       uint16(c.Laddr.Port),
+		ListenAddr: net.JoinHostPort(c.Laddr.IP, strconv.FormatUint(uint64(c.Laddr.Port), 10)),
+		Proto:      "tcp",
+		Family:     "ipv4",
+		PID:        c.Pid,
+	}
+	if c.Type == syscall.SOCK_DGRAM {
+		p.Proto = "udp"
+	}
+	if c.Family == syscall.AF_INET6 {
+		p.Family = "ipv6"
+	}
+	if c.Raddr.IP != "" && c.Raddr.Port != 0 {
+		p.RemoteIP = c.Raddr.IP
+		p.RemotePort = uint16(c.Raddr.Port)
+		p.RemoteAddr = net.JoinHostPort(c.Raddr.IP, strconv.FormatUint(uint64(c.Raddr.Port), 10))
 	}
 
-	return result, nil
+	if p.Proto == "tcp" {
+		p.Status = strings.ToUpper(c.Status)
+		p.Listening = p.Status == tcpStateListen
+	} else {
+		// UDP 无连接状态，未指定远端的套接字视为监听
+		p.Listening = p.RemoteAddr == ""
+	}
+	return p
 }
 
-func KillProcessesByPort(port uint16) (int, error) {
-	ports, err := GetPortListSimple(port)
-	if err != nil {
-		return 0, err
+// matchPortFilter 判断连接是否满足过滤条件
+func matchPortFilter(p model.PortInfo, f model.PortFilter) bool {
+	if f.Proto != "" && !strings.EqualFold(f.Proto, p.Proto) {
+		return false
+	}
+	if f.Family != "" && !strings.EqualFold(f.Family, p.Family) {
+		return false
+	}
+	if f.PID != 0 && f.PID != p.PID {
+		return false
+	}
+	if f.MinPort != 0 && p.Port < f.MinPort {
+		return false
+	}
+	if f.MaxPort != 0 && p.Port > f.MaxPort {
+		return false
+	}
+	if f.ListeningOnly && !p.Listening {
+		return false
+	}
+	if len(f.States) > 0 {
+		matched := false
+		for _, s := range f.States {
+			if strings.EqualFold(s, p.Status) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
 	}
+	return true
+}
 
-	killed := 0
-	for _, p := range ports {
-		if err := KillProcess(p.PID); err == nil {
-			killed++
+// processName 查询进程名（同一次扫描内缓存）
+func processName(cache map[int32]string, pid int32) string {
+	if name, ok := cache[pid]; ok {
+		return name
+	}
+	name := "Unknown"
+	if pid == 0 {
+		name = "System Idle"
+	} else if p, err := process.NewProcess(pid); err == nil {
+		if n, err := p.Name(); err == nil && n != "" {
+			name = n
 		}
 	}
+	cache[pid] = name
+	return name
+}
 
-	return killed, nil
+// sortPorts 按协议、本地端口、本地地址、远端地址排序
+func sortPorts(ports []model.PortInfo) {
+	sort.Slice(ports, func(i, j int) bool {
+		a, b := ports[i], ports[j]
+		if a.Proto != b.Proto {
+			return a.Proto < b.Proto
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.ListenAddr != b.ListenAddr {
+			return a.ListenAddr < b.ListenAddr
+		}
+		return a.RemoteAddr < b.RemoteAddr
+	})
 }
 
+// GetPortList 获取本地端口为 port 的所有连接
 func GetPortList(port uint16) ([]model.PortInfo, error) {
-	return GetPortListSimple(port)
+	result, err := ListConnections(model.PortFilter{MinPort: port, MaxPort: port})
+	if err != nil {
+		return nil, err
+	}
+	return result.Ports, nil
 }
 
+// GetListeningPorts 获取端口号不小于 minPort 的监听端口（TCP LISTEN 及未连接的 UDP）
 func GetListeningPorts(minPort uint16) ([]model.PortInfo, error) {
-	psScript := `$conns = Get-NetTCPConnection -State Listen -ErrorAction SilentlyContinue | Where-Object { $_.LocalPort -ge ` + strconv.FormatUint(uint64(minPort), 10) + ` }
-foreach ($c in $conns) {
-  $proc = Get-Process -Id $c.OwningProcess -ErrorAction SilentlyContinue
-  $name = if ($proc) { $proc.ProcessName } else { "Unknown" }
-  "$($c.LocalAddress):$($c.LocalPort)|$($c.RemoteAddress):$($c.RemotePort)|$($c.State)|$($c.OwningProcess)|$name"
-}`
-
-	output, err := winapi.HiddenCmd("powershell", "-NoProfile", "-Command", psScript).Output()
+	result, err := ListConnections(model.PortFilter{MinPort: minPort, ListeningOnly: true})
 	if err != nil {
 		return nil, err
 	}
-
-	var result []model.PortInfo
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		parts := strings.SplitN(line, "|", 5)
-		if len(parts) < 5 {
-			continue
-		}
-
-		addrParts := strings.SplitN(parts[0], ":", 2)
-		localPort, _ := strconv.ParseUint(strings.TrimSpace(addrParts[1]), 10, 64)
-		state := strings.TrimSpace(parts[2])
-		pid, _ := strconv.ParseInt(strings.TrimSpace(parts[3]), 10, 64)
-		procName := strings.TrimSpace(parts[4])
-
-		result = append(result, model.PortInfo{
-			ListenAddr:  strings.TrimSpace(parts[0]),
-			Port:        uint16(localPort),
-			Proto:       "tcp",
-			PID:         int32(pid),
-			ProcessName: procName,
-			Status:      state,
-		})
-	}
-
-	return result, nil
-}
\ No newline at end of file
+	return result.Ports, nil
+}
@@ -0,0 +1,337 @@
+package monitor
+
+import (
+	"sort"
+	"sync"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/storage"
+	"win-cleaner/pkg/timeutil"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	portWatchFile        = "port_watch.json"
+	portHistoryPerPort   = 20 // 每个端口保留的占用记录数
+	maxWatchedPorts      = 100
+	PortWatchEventOccupy = "occupied" // 端口从空闲变为被占用
+	PortWatchEventFree   = "freed"    // 端口被释放
+	PortWatchEventChange = "changed"  // 仍被占用，但占用进程变了
+)
+
+// 上一次轮询时各关注端口的占用进程
+var (
+	portWatchMu    sync.Mutex
+	portWatchState map[uint16]map[int32]model.PortHolder
+)
+
+func loadPortWatch() (*model.PortWatchConfig, error) {
+	var cfg model.PortWatchConfig
+	if err := storage.Load(portWatchFile, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// AddWatchedPort 把端口加入关注列表，已存在时更新备注
+func AddWatchedPort(port uint16, label string) error {
+	if port == 0 {
+		return i18n.Errorf("portwatch.invalid_port")
+	}
+	var cfg model.PortWatchConfig
+	return storage.Update(portWatchFile, &cfg, func() error {
+		for i, w := range cfg.Ports {
+			if w.Port == port {
+				cfg.Ports[i].Label = label
+				return nil
+			}
+		}
+		if len(cfg.Ports) >= maxWatchedPorts {
+			return i18n.Errorf("portwatch.too_many", maxWatchedPorts)
+		}
+		cfg.Ports = append(cfg.Ports, model.WatchedPort{Port: port, Label: label})
+		sort.Slice(cfg.Ports, func(i, j int) bool { return cfg.Ports[i].Port < cfg.Ports[j].Port })
+		return nil
+	})
+}
+
+// RemoveWatchedPort 取消关注端口，同时删除该端口的占用记录
+func RemoveWatchedPort(port uint16) error {
+	var cfg model.PortWatchConfig
+	err := storage.Update(portWatchFile, &cfg, func() error {
+		ports := cfg.Ports[:0]
+		for _, w := range cfg.Ports {
+			if w.Port != port {
+				ports = append(ports, w)
+			}
+		}
+		cfg.Ports = ports
+		history := cfg.History[:0]
+		for _, h := range cfg.History {
+			if h.Port != port {
+				history = append(history, h)
+			}
+		}
+		cfg.History = history
+		return nil
+	})
+	if err == nil {
+		portWatchMu.Lock()
+		delete(portWatchState, port)
+		portWatchMu.Unlock()
+	}
+	return err
+}
+
+// HasWatchedPorts 关注列表是否非空（为空时后台无需轮询）
+func HasWatchedPorts() bool {
+	cfg, err := loadPortWatch()
+	return err == nil && len(cfg.Ports) > 0
+}
+
+// GetPortWatchStatus 关注端口的当前占用情况及占用历史
+func GetPortWatchStatus() ([]model.PortWatchStatus, error) {
+	cfg, err := loadPortWatch()
+	if err != nil {
+		return nil, err
+	}
+	current, err := watchedHolders(cfg.Ports)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []model.PortWatchStatus{}
+	for _, w := range cfg.Ports {
+		st := model.PortWatchStatus{
+			WatchedPort: w,
+			Holders:     sortedHolders(current[w.Port]),
+			History:     []model.PortHolderRecord{},
+		}
+		st.Occupied = len(st.Holders) > 0
+		for _, h := range cfg.History {
+			if h.Port == w.Port {
+				st.History = append(st.History, h)
+			}
+		}
+		// 最近的记录在前
+		sort.SliceStable(st.History, func(i, j int) bool {
+			return timeutil.Before(st.History[j].Start, st.History[i].Start)
+		})
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// PollPortWatch 检查关注端口的占用变化，更新占用历史并返回变化事件
+//
+// 首次轮询（以及新加入的端口）只记录当前状态，不产生事件。
+func PollPortWatch() ([]model.PortWatchEvent, error) {
+	cfg, err := loadPortWatch()
+	if err != nil || len(cfg.Ports) == 0 {
+		return nil, err
+	}
+	current, err := watchedHolders(cfg.Ports)
+	if err != nil {
+		return nil, err
+	}
+	now := timeutil.Now()
+
+	portWatchMu.Lock()
+	prevState, initialized := portWatchState, portWatchState != nil
+	portWatchState = current
+	portWatchMu.Unlock()
+
+	var events []model.PortWatchEvent
+	if initialized {
+		for _, w := range cfg.Ports {
+			prev, seen := prevState[w.Port]
+			if !seen {
+				// 新加入关注的端口，下一轮才开始比较
+				continue
+			}
+			if e, ok := portWatchEvent(w, prev, current[w.Port], now); ok {
+				events = append(events, e)
+			}
+		}
+	}
+
+	// 没有记录开始或结束时不写文件，避免每次轮询都重写 port_watch.json
+	if !portHistoryChanged(cfg, current) {
+		return events, nil
+	}
+	if err := updatePortHistory(current, now); err != nil {
+		return events, err
+	}
+	return events, nil
+}
+
+// portHistoryChanged 未结束的占用记录与当前占用进程是否不一致（需要开始或结束记录）
+func portHistoryChanged(cfg *model.PortWatchConfig, current map[uint16]map[int32]model.PortHolder) bool {
+	watched := make(map[uint16]bool)
+	for _, w := range cfg.Ports {
+		watched[w.Port] = true
+	}
+	open := 0
+	for _, h := range cfg.History {
+		if h.End == "" {
+			if _, ok := current[h.Port][h.PID]; !ok {
+				return true
+			}
+			open++
+		}
+	}
+	holders := 0
+	for port, m := range current {
+		if watched[port] {
+			holders += len(m)
+		}
+	}
+	return holders != open
+}
+
+// portWatchEvent 比较前后两次的占用进程
+func portWatchEvent(w model.WatchedPort, prev, cur map[int32]model.PortHolder, now string) (model.PortWatchEvent, bool) {
+	e := model.PortWatchEvent{WatchedPort: w, Time: now}
+	switch {
+	case len(prev) == 0 && len(cur) > 0:
+		e.Event = PortWatchEventOccupy
+		e.Holders = sortedHolders(cur)
+	case len(prev) > 0 && len(cur) == 0:
+		e.Event = PortWatchEventFree
+		e.Holders = sortedHolders(prev)
+	case len(cur) > 0 && !samePIDs(prev, cur):
+		e.Event = PortWatchEventChange
+		e.Holders = sortedHolders(cur)
+	default:
+		return e, false
+	}
+	return e, true
+}
+
+// updatePortHistory 为新出现的占用进程开始记录，为已不再占用的记录填写结束时间
+func updatePortHistory(current map[uint16]map[int32]model.PortHolder, now string) error {
+	var cfg model.PortWatchConfig
+	return storage.Update(portWatchFile, &cfg, func() error {
+		watched := make(map[uint16]bool)
+		for _, w := range cfg.Ports {
+			watched[w.Port] = true
+		}
+		open := make(map[uint16]map[int32]bool)
+		for i, h := range cfg.History {
+			if h.End != "" {
+				continue
+			}
+			if _, ok := current[h.Port][h.PID]; ok {
+				if open[h.Port] == nil {
+					open[h.Port] = make(map[int32]bool)
+				}
+				open[h.Port][h.PID] = true
+			} else {
+				cfg.History[i].End = now
+			}
+		}
+		for port, holders := range current {
+			if !watched[port] {
+				continue
+			}
+			for _, h := range sortedHolders(holders) {
+				if open[port][h.PID] {
+					continue
+				}
+				cfg.History = append(cfg.History, model.PortHolderRecord{PortHolder: h, Port: port, Start: now})
+			}
+		}
+		cfg.History = trimPortHistory(cfg.History)
+		return nil
+	})
+}
+
+// trimPortHistory 每个端口只保留最近 portHistoryPerPort 条记录
+func trimPortHistory(history []model.PortHolderRecord) []model.PortHolderRecord {
+	count := make(map[uint16]int)
+	keep := make([]bool, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		port := history[i].Port
+		if count[port] < portHistoryPerPort {
+			keep[i] = true
+			count[port]++
+		}
+	}
+	out := history[:0]
+	for i, h := range history {
+		if keep[i] {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// watchedHolders 查询关注端口当前的监听进程
+func watchedHolders(ports []model.WatchedPort) (map[uint16]map[int32]model.PortHolder, error) {
+	result := make(map[uint16]map[int32]model.PortHolder)
+	if len(ports) == 0 {
+		return result, nil
+	}
+	watched := make(map[uint16]bool)
+	for _, w := range ports {
+		watched[w.Port] = true
+		result[w.Port] = make(map[int32]model.PortHolder)
+	}
+	conns, err := ListConnections(model.PortFilter{ListeningOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	cmdlines := make(map[int32]string)
+	for _, c := range conns.Ports {
+		if !watched[c.Port] {
+			continue
+		}
+		if _, ok := result[c.Port][c.PID]; ok {
+			continue
+		}
+		result[c.Port][c.PID] = model.PortHolder{
+			PID:        c.PID,
+			Name:       c.ProcessName,
+			Cmdline:    processCmdline(cmdlines, c.PID),
+			Proto:      c.Proto,
+			ListenAddr: c.ListenAddr,
+		}
+	}
+	return result, nil
+}
+
+// processCmdline 查询进程命令行（同一次轮询内缓存），无权限时为空
+func processCmdline(cache map[int32]string, pid int32) string {
+	if cmd, ok := cache[pid]; ok {
+		return cmd
+	}
+	cmd := ""
+	if p, err := process.NewProcess(pid); err == nil {
+		cmd, _ = p.Cmdline()
+	}
+	cache[pid] = cmd
+	return cmd
+}
+
+func sortedHolders(m map[int32]model.PortHolder) []model.PortHolder {
+	holders := make([]model.PortHolder, 0, len(m))
+	for _, h := range m {
+		holders = append(holders, h)
+	}
+	sort.Slice(holders, func(i, j int) bool { return holders[i].PID < holders[j].PID })
+	return holders
+}
+
+func samePIDs(a, b map[int32]model.PortHolder) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for pid := range a {
+		if _, ok := b[pid]; !ok {
+			return false
+		}
+	}
+	return true
+}
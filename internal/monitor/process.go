@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"win-cleaner/internal/model"
+	"win-cleaner/internal/settings"
 
 	"github.com/shirou/gopsutil/v3/process"
 )
@@ -20,7 +21,7 @@ func GetProcessList() ([]model.ProcessInfo, error) {
 	for _, p := range procs {
 		_, _ = p.CPUPercent()
 	}
-	time.Sleep(500 * time.Millisecond)
+	time.Sleep(time.Duration(settings.Get().CPUSampleMillis) * time.Millisecond)
 
 	var result []model.ProcessInfo
 	for _, p := range procs {
@@ -62,8 +63,16 @@ func GetProcessList() ([]model.ProcessInfo, error) {
 	return result, nil
 }
 
-// KillProcess 结束指定进程
-func KillProcess(pid int32) error {
+// KillProcess 结束指定进程；受保护的进程返回 *ProtectedProcessError，override 为 true 时跳过可覆盖的保护
+func KillProcess(pid int32, override bool) error {
+	if err := CheckKillAllowed(pid, override); err != nil {
+		return err
+	}
+	return killPID(pid)
+}
+
+// killPID 强制结束进程（不检查保护策略）
+func killPID(pid int32) error {
 	p, err := process.NewProcess(pid)
 	if err != nil {
 		return err
@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/storage"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const protectionFile = "process_protection.json"
+
+// 进程受保护的原因
+const (
+	ProtectSelf     = "self"      // 本程序自身
+	ProtectSystem   = "system"    // PID 0/4 等内核进程
+	ProtectCritical = "critical"  // 内置的系统关键进程
+	ProtectBlocked  = "blocklist" // 用户加入了禁止结束列表
+	ProtectUnknown  = "unknown"   // 读不到进程名，无法判断是否关键进程
+)
+
+// ErrorCodeProtectedProcess 前端识别受保护进程错误的代码
+const ErrorCodeProtectedProcess = "protected_process"
+
+// ProtectedProcessError 拒绝结束受保护进程时返回的错误
+type ProtectedProcessError struct {
+	PID         int32  `json:"pid"`
+	Name        string `json:"name"`
+	Reason      string `json:"reason"`      // self / system / critical / blocklist / unknown
+	Overridable bool   `json:"overridable"` // 用户确认后是否可以强行结束
+}
+
+func (e *ProtectedProcessError) Error() string {
+	return i18n.T("process.protected."+e.Reason, e.Name, e.PID)
+}
+
+// ErrorCode 供 Wails 错误格式化识别
+func (e *ProtectedProcessError) ErrorCode() string {
+	return ErrorCodeProtectedProcess
+}
+
+// GetProcessProtection 获取进程保护设置（内置关键进程列表只读）
+func GetProcessProtection() (model.ProcessProtection, error) {
+	var p model.ProcessProtection
+	if err := storage.Load(protectionFile, &p); err != nil {
+		return p, err
+	}
+	p.Critical = append([]string{}, criticalProcesses...)
+	sort.Strings(p.Critical)
+	return p, nil
+}
+
+// SetProcessProtection 保存用户的允许结束列表和禁止结束列表（进程名，不区分大小写）
+func SetProcessProtection(p model.ProcessProtection) error {
+	cfg := model.ProcessProtection{
+		Allow: normalizeNames(p.Allow),
+		Block: normalizeNames(p.Block),
+	}
+	return storage.Save(protectionFile, cfg)
+}
+
+// CheckKillAllowed 判断是否允许结束进程，受保护时返回 *ProtectedProcessError；
+// override 为 true 时跳过可覆盖的保护（自身和内核进程始终不可结束）
+func CheckKillAllowed(pid int32, override bool) error {
+	name := ""
+	if p, err := process.NewProcess(pid); err == nil {
+		name, _ = p.Name()
+	}
+	reason := protectReason(pid, name)
+	if reason == "" {
+		return nil
+	}
+	e := &ProtectedProcessError{
+		PID:         pid,
+		Name:        name,
+		Reason:      reason,
+		Overridable: reason == ProtectCritical || reason == ProtectBlocked || reason == ProtectUnknown,
+	}
+	if override && e.Overridable {
+		return nil
+	}
+	return e
+}
+
+// protectReason 进程受保护的原因，不受保护时返回空
+//
+// 优先级：自身 / 内核进程 > 内置关键进程 > 用户允许列表 > 用户禁止列表。
+// 允许列表只放宽用户自己加的保护，关键进程即使在允许列表中也要每次确认（Override）。
+// 进程名为空（权限不足等读不到）时无法排除关键进程，按 ProtectUnknown 处理，需用户确认。
+func protectReason(pid int32, name string) string {
+	if pid == int32(os.Getpid()) {
+		return ProtectSelf
+	}
+	if pid <= 4 {
+		return ProtectSystem
+	}
+
+	key := strings.ToLower(name)
+	switch {
+	case key == "":
+		return ProtectUnknown
+	case containsName(criticalProcesses, key):
+		return ProtectCritical
+	}
+
+	var cfg model.ProcessProtection
+	_ = storage.Load(protectionFile, &cfg)
+	if !containsName(cfg.Allow, key) && containsName(cfg.Block, key) {
+		return ProtectBlocked
+	}
+	return ""
+}
+
+func containsName(names []string, key string) bool {
+	for _, n := range names {
+		if strings.ToLower(n) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeNames 去除空白、转小写并去重
+func normalizeNames(names []string) []string {
+	seen := make(map[string]bool)
+	out := []string{}
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,9 @@
+package monitor
+
+// criticalProcesses Linux 关键进程，结束后系统或桌面会话会失去响应
+var criticalProcesses = []string{
+	"init", "systemd", "kthreadd",
+	"systemd-journald", "systemd-udevd", "systemd-logind",
+	"dbus-daemon", "dbus-broker", "polkitd",
+	"xorg", "xwayland", "gnome-shell", "kwin_wayland", "kwin_x11",
+}
@@ -0,0 +1,44 @@
+package monitor
+
+import (
+	"os"
+	"testing"
+
+	"win-cleaner/internal/model"
+	"win-cleaner/pkg/datadir"
+)
+
+func TestProtectReason(t *testing.T) {
+	datadir.SetOverride(t.TempDir())
+	t.Cleanup(func() { datadir.SetOverride("") })
+
+	critical := criticalProcesses[0]
+	if err := SetProcessProtection(model.ProcessProtection{
+		Allow: []string{critical, "node.exe", "both.exe"},
+		Block: []string{"backup.exe", "both.exe"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		pid  int32
+		proc string
+		want string
+	}{
+		{"self", int32(os.Getpid()), "", ProtectSelf},
+		{"kernel", 4, "System", ProtectSystem},
+		{"unknown name", 1000, "", ProtectUnknown},
+		// 允许列表不能解除内置关键进程的保护
+		{"allowlisted critical", 1000, critical, ProtectCritical},
+		{"blocklist", 1000, "Backup.exe", ProtectBlocked},
+		{"allowlist relaxes blocklist", 1000, "both.exe", ""},
+		{"allowlisted ordinary", 1000, "node.exe", ""},
+		{"ordinary", 1000, "notepad.exe", ""},
+	}
+	for _, tc := range cases {
+		if got := protectReason(tc.pid, tc.proc); got != tc.want {
+			t.Errorf("%s: protectReason(%d, %q) = %q, want %q", tc.name, tc.pid, tc.proc, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package monitor
+
+// criticalProcesses Windows 关键进程，结束后可能蓝屏、注销或系统功能失效
+var criticalProcesses = []string{
+	"system", "registry", "memory compression", "secure system",
+	"smss.exe", "csrss.exe", "wininit.exe", "winlogon.exe",
+	"services.exe", "lsass.exe", "lsaiso.exe", "svchost.exe",
+	"dwm.exe", "fontdrvhost.exe", "sihost.exe", "ctfmon.exe",
+	"msmpeng.exe",
+}
@@ -0,0 +1,247 @@
+// Package publicip 公网 IP 查询：可替换的 HTTPS 服务提供方，按顺序失败切换并缓存结果
+package publicip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"win-cleaner/internal/i18n"
+)
+
+// 内置服务名称，设置中按逗号分隔的顺序依次尝试
+const (
+	ProviderIpify    = "ipify"
+	ProviderIfconfig = "ifconfig.co"
+	ProviderCustom   = "custom" // 自建服务，地址见设置 public_ip_custom_url
+
+	// DefaultProviders 默认依次尝试的服务
+	DefaultProviders = ProviderIpify + "," + ProviderIfconfig
+)
+
+var builtin = map[string]string{
+	ProviderIpify:    "https://api.ipify.org?format=json",
+	ProviderIfconfig: "https://ifconfig.co/json",
+}
+
+// maxResponse 响应只需要一个 IP，限制读取长度
+const maxResponse = 4 << 10
+
+// Provider 公网 IP 服务
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, client *http.Client) (string, error)
+}
+
+// HTTPProvider 通过 GET 请求查询的服务，响应为 {"ip": "..."} 或纯文本 IP
+type HTTPProvider struct {
+	ProviderName string
+	URL          string
+}
+
+func (p HTTPProvider) Name() string { return p.ProviderName }
+
+func (p HTTPProvider) Lookup(ctx context.Context, client *http.Client) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json, text/plain")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", i18n.Errorf("publicip.bad_status", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse))
+	if err != nil {
+		return "", err
+	}
+	return parseIP(body)
+}
+
+// parseIP 解析 JSON 的 ip 字段或纯文本
+func parseIP(body []byte) (string, error) {
+	text := strings.TrimSpace(string(body))
+	if strings.HasPrefix(text, "{") {
+		var v struct {
+			IP string `json:"ip"`
+		}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return "", i18n.Errorf("publicip.bad_response")
+		}
+		text = v.IP
+	}
+	ip := net.ParseIP(text)
+	if ip == nil {
+		return "", i18n.Errorf("publicip.bad_response")
+	}
+	return ip.String(), nil
+}
+
+// Names 可在设置中使用的服务名称
+func Names() []string {
+	return []string{ProviderIpify, ProviderIfconfig, ProviderCustom}
+}
+
+// ParseProviders 按设置的顺序构造服务列表
+func ParseProviders(list, customURL string) ([]Provider, error) {
+	var providers []Provider
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		switch {
+		case name == ProviderCustom:
+			if customURL == "" {
+				return nil, i18n.Errorf("publicip.custom_url_required")
+			}
+			if err := ValidateCustomURL(customURL); err != nil {
+				return nil, err
+			}
+			providers = append(providers, HTTPProvider{ProviderName: name, URL: customURL})
+		case builtin[name] != "":
+			providers = append(providers, HTTPProvider{ProviderName: name, URL: builtin[name]})
+		default:
+			return nil, i18n.Errorf("publicip.unknown_provider", name)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, i18n.Errorf("publicip.no_provider")
+	}
+	return providers, nil
+}
+
+// ValidateCustomURL 自建服务地址必须是 HTTPS，空字符串表示未设置
+func ValidateCustomURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return i18n.Errorf("publicip.invalid_url", raw)
+	}
+	if u.Scheme != "https" {
+		return i18n.Errorf("publicip.https_required", raw)
+	}
+	return nil
+}
+
+// Result 查询结果
+type Result struct {
+	IP       string
+	Provider string
+	At       time.Time
+}
+
+// Resolver 依次尝试各服务，成功结果缓存 ttl；全部失败后 RetryAfter 内不再请求，返回上次的结果
+type Resolver struct {
+	Client     *http.Client  // 为空时使用 5 秒超时的默认客户端；重定向规则总是替换为只允许 HTTPS
+	RetryAfter time.Duration // 为 0 时为 1 分钟
+
+	mu       sync.Mutex
+	last     Result
+	lastErr  error
+	failedAt time.Time
+	inflight *lookupCall // 正在进行的查询，同时到达的请求共用结果
+}
+
+// lookupCall 一次进行中的网络查询
+type lookupCall struct {
+	done chan struct{}
+	res  Result
+	err  error
+}
+
+// Lookup 查询公网 IP；force 为 true 时忽略缓存
+//
+// 网络请求期间不持有锁，已有查询在进行时等待它的结果，不重复请求。
+func (r *Resolver) Lookup(ctx context.Context, providers []Provider, ttl time.Duration, force bool) (Result, error) {
+	r.mu.Lock()
+	retry := r.RetryAfter
+	if retry == 0 {
+		retry = time.Minute
+	}
+	fresh := r.last.IP != "" && r.lastErr == nil && time.Since(r.last.At) < ttl
+	backoff := r.lastErr != nil && time.Since(r.failedAt) < retry
+	if !force && (fresh || backoff) {
+		res, err := r.last, r.lastErr
+		r.mu.Unlock()
+		return res, err
+	}
+	if c := r.inflight; c != nil {
+		r.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.res, c.err
+		case <-ctx.Done():
+			return Result{}, ctx.Err()
+		}
+	}
+	c := &lookupCall{done: make(chan struct{})}
+	r.inflight = c
+	r.mu.Unlock()
+
+	ip, name, err := r.query(ctx, providers)
+
+	r.mu.Lock()
+	if err == nil {
+		r.last = Result{IP: ip, Provider: name, At: time.Now()}
+		r.lastErr = nil
+	} else {
+		r.lastErr = err
+		r.failedAt = time.Now()
+	}
+	c.res, c.err = r.last, r.lastErr
+	r.inflight = nil
+	r.mu.Unlock()
+	close(c.done)
+	return c.res, c.err
+}
+
+// query 依次请求各服务，返回第一个成功的结果
+func (r *Resolver) query(ctx context.Context, providers []Provider) (string, string, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	if r.Client != nil {
+		copied := *r.Client
+		client = &copied
+	}
+	client.CheckRedirect = httpsOnlyRedirect
+
+	var errs []string
+	for _, p := range providers {
+		ip, err := p.Lookup(ctx, client)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", p.Name(), err))
+			continue
+		}
+		return ip, p.Name(), nil
+	}
+	return "", "", i18n.Errorf("publicip.all_failed", strings.Join(errs, "; "))
+}
+
+// maxRedirects 与 net/http 默认的重定向次数上限相同
+const maxRedirects = 10
+
+// httpsOnlyRedirect 拒绝跳转到非 HTTPS 地址，避免查询被降级为明文请求
+func httpsOnlyRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return i18n.Errorf("publicip.insecure_redirect", req.URL.Redacted())
+	}
+	if len(via) >= maxRedirects {
+		return i18n.Errorf("publicip.too_many_redirects", maxRedirects)
+	}
+	return nil
+}
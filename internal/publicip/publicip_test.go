@@ -0,0 +1,217 @@
+package publicip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testServer 返回固定响应的 HTTPS 服务，并统计请求次数
+type testServer struct {
+	*httptest.Server
+	hits   atomic.Int32
+	status atomic.Int32
+	body   string
+}
+
+func newTestServer(t *testing.T, body string) *testServer {
+	t.Helper()
+	s := &testServer{body: body}
+	s.status.Store(http.StatusOK)
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.hits.Add(1)
+		w.WriteHeader(int(s.status.Load()))
+		_, _ = w.Write([]byte(s.body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) provider(name string) Provider {
+	return HTTPProvider{ProviderName: name, URL: s.URL}
+}
+
+// resolverFor 信任测试服务证书的 Resolver（httptest 的各 TLS 服务共用同一证书）
+func resolverFor(s *testServer) *Resolver {
+	return &Resolver{Client: s.Client(), RetryAfter: time.Hour}
+}
+
+func TestLookupParsesJSONAndText(t *testing.T) {
+	cases := []struct {
+		name, body, want string
+	}{
+		{"json", `{"ip": "203.0.113.7"}`, "203.0.113.7"},
+		{"text", "2001:db8::1\n", "2001:db8::1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServer(t, tc.body)
+			res, err := resolverFor(s).Lookup(context.Background(), []Provider{s.provider(tc.name)}, time.Minute, false)
+			if err != nil {
+				t.Fatalf("Lookup: %v", err)
+			}
+			if res.IP != tc.want || res.Provider != tc.name {
+				t.Errorf("Lookup = %+v, want IP %s from %s", res, tc.want, tc.name)
+			}
+		})
+	}
+}
+
+func TestLookupRejectsInvalidBody(t *testing.T) {
+	s := newTestServer(t, "<html>not an ip</html>")
+	if _, err := resolverFor(s).Lookup(context.Background(), []Provider{s.provider("bad")}, time.Minute, false); err == nil {
+		t.Fatal("Lookup succeeded on a non-IP body")
+	}
+}
+
+func TestLookupFailsOverOnBadStatus(t *testing.T) {
+	bad := newTestServer(t, `{"ip": "198.51.100.1"}`)
+	bad.status.Store(http.StatusServiceUnavailable)
+	good := newTestServer(t, `{"ip": "203.0.113.7"}`)
+
+	res, err := resolverFor(good).Lookup(context.Background(), []Provider{bad.provider("bad"), good.provider("good")}, time.Minute, false)
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if res.IP != "203.0.113.7" || res.Provider != "good" {
+		t.Errorf("Lookup = %+v, want the second provider", res)
+	}
+	if bad.hits.Load() != 1 || good.hits.Load() != 1 {
+		t.Errorf("hits = %d/%d, want 1/1", bad.hits.Load(), good.hits.Load())
+	}
+}
+
+func TestLookupReturnsStaleDuringRetryWindow(t *testing.T) {
+	s := newTestServer(t, `{"ip": "203.0.113.7"}`)
+	r := resolverFor(s)
+	providers := []Provider{s.provider("a")}
+	ctx := context.Background()
+
+	// ttl 为 0：每次都重新请求
+	if _, err := r.Lookup(ctx, providers, 0, false); err != nil {
+		t.Fatalf("first Lookup: %v", err)
+	}
+	s.status.Store(http.StatusInternalServerError)
+
+	res, err := r.Lookup(ctx, providers, 0, false)
+	if err == nil {
+		t.Fatal("Lookup succeeded while every provider fails")
+	}
+	if res.IP != "203.0.113.7" {
+		t.Errorf("stale IP = %q, want the previous result", res.IP)
+	}
+
+	// RetryAfter 内不再请求，仍返回上次的结果和错误
+	hits := s.hits.Load()
+	res, err = r.Lookup(ctx, providers, 0, false)
+	if err == nil || res.IP != "203.0.113.7" {
+		t.Errorf("Lookup in retry window = %+v, %v", res, err)
+	}
+	if s.hits.Load() != hits {
+		t.Errorf("requested again inside the retry window")
+	}
+
+	// 恢复后 force 立即重新请求
+	s.status.Store(http.StatusOK)
+	if _, err := r.Lookup(ctx, providers, 0, true); err != nil {
+		t.Errorf("forced Lookup after recovery: %v", err)
+	}
+}
+
+func TestLookupCachesUntilTTLOrForce(t *testing.T) {
+	s := newTestServer(t, `{"ip": "203.0.113.7"}`)
+	r := resolverFor(s)
+	providers := []Provider{s.provider("a")}
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := r.Lookup(ctx, providers, time.Hour, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := s.hits.Load(); got != 1 {
+		t.Errorf("hits with cache = %d, want 1", got)
+	}
+	if _, err := r.Lookup(ctx, providers, time.Hour, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.hits.Load(); got != 2 {
+		t.Errorf("hits after force = %d, want 2", got)
+	}
+}
+
+func TestLookupSharesInflightRequest(t *testing.T) {
+	release := make(chan struct{})
+	var hits atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		_, _ = w.Write([]byte("203.0.113.7"))
+	}))
+	defer srv.Close()
+	r := &Resolver{Client: srv.Client()}
+	providers := []Provider{HTTPProvider{ProviderName: "slow", URL: srv.URL}}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, err := r.Lookup(context.Background(), providers, time.Hour, false); err != nil || res.IP != "203.0.113.7" {
+				t.Errorf("Lookup = %+v, %v", res, err)
+			}
+		}()
+	}
+	// 请求进行中时锁已释放，其他调用不会被网络请求阻塞在锁上
+	deadline := time.Now().Add(5 * time.Second)
+	for hits.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !r.mu.TryLock() {
+		t.Error("mutex held during the network request")
+	} else {
+		r.mu.Unlock()
+	}
+	close(release)
+	wg.Wait()
+	if got := hits.Load(); got != 1 {
+		t.Errorf("hits = %d, want 1 shared request", got)
+	}
+}
+
+func TestLookupRejectsRedirectToHTTP(t *testing.T) {
+	var plainHits atomic.Int32
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plainHits.Add(1)
+		_, _ = w.Write([]byte("203.0.113.7"))
+	}))
+	defer plain.Close()
+	redirect := httptest.NewTLSServer(http.RedirectHandler(plain.URL, http.StatusFound))
+	defer redirect.Close()
+
+	r := &Resolver{Client: redirect.Client()}
+	_, err := r.Lookup(context.Background(), []Provider{HTTPProvider{ProviderName: "r", URL: redirect.URL}}, time.Minute, false)
+	if err == nil {
+		t.Fatal("Lookup followed a redirect to plain HTTP")
+	}
+	if plainHits.Load() != 0 {
+		t.Error("plain HTTP target was requested")
+	}
+}
+
+func TestParseProvidersRequiresHTTPSCustomURL(t *testing.T) {
+	if _, err := ParseProviders(ProviderCustom, "http://example.com/ip"); err == nil {
+		t.Error("accepted a plain HTTP custom URL")
+	}
+	if _, err := ParseProviders(ProviderCustom, ""); err == nil {
+		t.Error("accepted custom provider without URL")
+	}
+	ps, err := ParseProviders("ifconfig.co, ipify, ipify", "")
+	if err != nil || len(ps) != 2 || ps[0].Name() != ProviderIfconfig {
+		t.Errorf("ParseProviders = %v, %v", ps, err)
+	}
+}
@@ -0,0 +1,140 @@
+// Package settings 应用设置：保存在数据目录的 settings.json，带默认值、校验和变更通知
+package settings
+
+import (
+	"sync"
+
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/model"
+	"win-cleaner/internal/publicip"
+	"win-cleaner/pkg/storage"
+)
+
+const settingsFile = "settings.json"
+
+// rule 单项设置的默认值与取值范围
+type rule struct {
+	key      string // 设置项名称的消息 key
+	field    func(*model.Settings) *int
+	def      int
+	min, max int
+}
+
+var rules = []rule{
+	{"settings.net_sample_interval", func(s *model.Settings) *int { return &s.NetSampleInterval }, 30, 5, 3600},
+	{"settings.public_ip_cache_minutes", func(s *model.Settings) *int { return &s.PublicIPCacheMinutes }, 5, 1, 1440},
+	{"settings.mem_history_days", func(s *model.Settings) *int { return &s.MemHistoryDays }, 90, 7, 3650},
+	{"settings.listening_port_min", func(s *model.Settings) *int { return &s.ListeningPortMin }, 1000, 1, 65535},
+	{"settings.large_file_limit", func(s *model.Settings) *int { return &s.LargeFileLimit }, 100, 10, 10000},
+	{"settings.cpu_sample_ms", func(s *model.Settings) *int { return &s.CPUSampleMillis }, 500, 100, 5000},
+	{"settings.port_watch_interval", func(s *model.Settings) *int { return &s.PortWatchInterval }, 5, 1, 300},
+	{"settings.conn_sample_interval", func(s *model.Settings) *int { return &s.ConnSampleInterval }, 60, 10, 3600},
+	{"settings.conn_history_days", func(s *model.Settings) *int { return &s.ConnHistoryDays }, 30, 1, 365},
+	{"settings.net_minute_days", func(s *model.Settings) *int { return &s.NetMinuteDays }, 7, 1, 90},
+	{"settings.net_hourly_days", func(s *model.Settings) *int { return &s.NetHourlyDays }, 365, 30, 3650},
+}
+
+var (
+	mu        sync.Mutex
+	current   model.Settings
+	loaded    bool
+	listeners []func(old, cur model.Settings)
+)
+
+// Defaults 默认设置
+func Defaults() model.Settings {
+	var s model.Settings
+	for _, r := range rules {
+		*r.field(&s) = r.def
+	}
+	s.Locale = i18n.DefaultLocale
+	s.PublicIPProviders = publicip.DefaultProviders
+	return s
+}
+
+// Get 获取当前设置（首次调用时从文件加载，缺失或超出范围的项使用默认值）
+func Get() model.Settings {
+	mu.Lock()
+	defer mu.Unlock()
+	if !loaded {
+		current = load()
+		loaded = true
+	}
+	return current
+}
+
+func load() model.Settings {
+	s := Defaults()
+	if err := storage.Load(settingsFile, &s); err != nil {
+		return Defaults()
+	}
+	for _, r := range rules {
+		if v := r.field(&s); *v < r.min || *v > r.max {
+			*v = r.def
+		}
+	}
+	if !i18n.Supported(s.Locale) {
+		s.Locale = i18n.DefaultLocale
+	}
+	if publicip.ValidateCustomURL(s.PublicIPCustomURL) != nil {
+		s.PublicIPCustomURL = ""
+	}
+	if _, err := publicip.ParseProviders(s.PublicIPProviders, s.PublicIPCustomURL); err != nil {
+		s.PublicIPProviders = publicip.DefaultProviders
+	}
+	return s
+}
+
+// Validate 校验设置是否在允许范围内
+func Validate(s model.Settings) error {
+	for _, r := range rules {
+		if v := *r.field(&s); v < r.min || v > r.max {
+			return i18n.Errorf("settings.out_of_range", i18n.T(r.key), r.min, r.max)
+		}
+	}
+	if !i18n.Supported(s.Locale) {
+		return i18n.Errorf("error.unsupported_locale", s.Locale)
+	}
+	if err := publicip.ValidateCustomURL(s.PublicIPCustomURL); err != nil {
+		return err
+	}
+	if _, err := publicip.ParseProviders(s.PublicIPProviders, s.PublicIPCustomURL); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update 校验并保存设置，保存成功后通知订阅者
+func Update(s model.Settings) (model.Settings, error) {
+	if err := Validate(s); err != nil {
+		return Get(), err
+	}
+
+	mu.Lock()
+	if !loaded {
+		current = load()
+		loaded = true
+	}
+	old := current
+	if err := storage.Save(settingsFile, s); err != nil {
+		mu.Unlock()
+		return old, err
+	}
+	current = s
+	fns := append([]func(old, cur model.Settings){}, listeners...)
+	mu.Unlock()
+
+	if old != s {
+		for _, fn := range fns {
+			fn(old, s)
+		}
+	}
+	return s, nil
+}
+
+// OnChange 订阅设置变更，回调在 Update 所在协程中执行
+func OnChange(fn func(old, cur model.Settings)) {
+	mu.Lock()
+	defer mu.Unlock()
+	listeners = append(listeners, fn)
+}
@@ -2,18 +2,39 @@ package main
 
 import (
 	"embed"
+	"flag"
+	"fmt"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
 	"github.com/wailsapp/wails/v2/pkg/options/windows"
 	"win-cleaner/internal/app"
+	"win-cleaner/internal/i18n"
+	"win-cleaner/internal/settings"
+	"win-cleaner/pkg/datadir"
+	"win-cleaner/pkg/winapi"
 )
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 func main() {
+	dataDir := flag.String("data-dir", "", i18n.T("cli.flag_data_dir", datadir.EnvVar))
+	migrate := flag.Bool("migrate-data", false, i18n.T("cli.flag_migrate"))
+	migrateFrom := flag.String("migrate-from", "", i18n.T("cli.flag_migrate_from"))
+	flag.Parse()
+
+	if *dataDir != "" {
+		datadir.SetOverride(*dataDir)
+	}
+	// 数据目录确定后才能读取设置中的界面语言
+	_ = i18n.SetLocale(settings.Get().Locale)
+	if *migrate {
+		os.Exit(migrateData(*migrateFrom))
+	}
+
 	application := app.NewApp()
 
 	err := wails.Run(&options.App{
@@ -28,6 +49,7 @@ func main() {
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        application.Startup,
 		OnShutdown:       application.Shutdown,
+		ErrorFormatter:   app.FormatError,
 		Bind: []interface{}{
 			application,
 		},
@@ -41,3 +63,35 @@ func main() {
 		println("Error:", err.Error())
 	}
 }
+
+// migrateData 把数据从旧目录移动到当前数据目录（便携模式、--data-dir 或环境变量指定的位置）
+func migrateData(from string) int {
+	if from == "" {
+		dir, err := datadir.DefaultDir()
+		if err != nil {
+			report(fmt.Sprintln(i18n.T("cli.no_default_dir"), app.LocalizeError(err)), true)
+			return 1
+		}
+		from = dir
+	}
+	moved, err := datadir.Migrate(from)
+	if err != nil {
+		report(fmt.Sprintln(i18n.T("cli.migrate_failed"), app.LocalizeError(err)), true)
+		return 1
+	}
+	report(i18n.T("cli.migrated", moved, from, datadir.Get()), false)
+	return 0
+}
+
+// report 输出命令行结果：GUI 程序没有自己的控制台，从命令行启动时附加到父控制台，否则弹出消息框
+func report(msg string, failed bool) {
+	if winapi.AttachParentConsole() {
+		if failed {
+			fmt.Fprint(os.Stderr, msg)
+		} else {
+			fmt.Fprint(os.Stdout, msg)
+		}
+		return
+	}
+	winapi.MessageBox("Win Cleaner", msg, failed)
+}
@@ -3,22 +3,119 @@ package datadir
 import (
 	"os"
 	"path/filepath"
+	"sync"
 )
 
-const appDir = ".wincleaner"
+const (
+	appDir = ".wincleaner"
 
-// Get 返回数据目录路径 (~/.wincleaner)，自动创建
+	// EnvVar 指定数据目录的环境变量
+	EnvVar = "WINCLEANER_DATA_DIR"
+	// PortableMarker 放在程序旁边的标记文件，存在时启用便携模式
+	PortableMarker = "wincleaner.portable"
+	// portableDir 便携模式下程序旁边的数据目录
+	portableDir = "data"
+)
+
+// 数据目录来源
+const (
+	SourceFlag     = "flag"     // 命令行参数 --data-dir
+	SourceEnv      = "env"      // 环境变量 WINCLEANER_DATA_DIR
+	SourcePortable = "portable" // 便携模式（程序旁边的 data 目录）
+	SourceHome     = "home"     // 用户主目录 ~/.wincleaner
+	SourceFallback = "fallback" // 无法获取主目录时使用程序所在目录
+)
+
+var (
+	mu       sync.Mutex
+	override string
+	resolved string
+	source   string
+)
+
+// SetOverride 指定数据目录（命令行参数），优先级最高；需在首次读写数据前调用
+func SetOverride(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	override = dir
+	resolved = ""
+}
+
+// Get 返回数据目录路径，自动创建
+//
+// 优先级：--data-dir 参数 > 环境变量 WINCLEANER_DATA_DIR > 便携模式 > ~/.wincleaner
 func Get() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return appDir
+	mu.Lock()
+	defer mu.Unlock()
+	if resolved == "" {
+		resolved, source = resolve()
 	}
-	dir := filepath.Join(home, appDir)
-	_ = os.MkdirAll(dir, 0755)
-	return dir
+	_ = os.MkdirAll(resolved, 0755)
+	return resolved
+}
+
+// Source 返回当前数据目录的来源（SourceFlag 等）
+func Source() string {
+	Get()
+	mu.Lock()
+	defer mu.Unlock()
+	return source
 }
 
 // FilePath 返回数据目录下指定文件的完整路径
 func FilePath(filename string) string {
 	return filepath.Join(Get(), filename)
 }
+
+// DefaultDir 默认数据目录 ~/.wincleaner
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, appDir), nil
+}
+
+func resolve() (string, string) {
+	if override != "" {
+		return absPath(override), SourceFlag
+	}
+	if dir := os.Getenv(EnvVar); dir != "" {
+		return absPath(dir), SourceEnv
+	}
+
+	exeDir, exeErr := executableDir()
+	if exeErr == nil {
+		if _, err := os.Stat(filepath.Join(exeDir, PortableMarker)); err == nil {
+			return filepath.Join(exeDir, portableDir), SourcePortable
+		}
+	}
+
+	if dir, err := DefaultDir(); err == nil {
+		return dir, SourceHome
+	}
+	// 主目录不可用时放在程序旁边，而不是依赖当前工作目录
+	if exeErr == nil {
+		return filepath.Join(exeDir, appDir), SourceFallback
+	}
+	return absPath(appDir), SourceFallback
+}
+
+// executableDir 程序所在目录（解析符号链接）
+func executableDir() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if real, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = real
+	}
+	return filepath.Dir(exe), nil
+}
+
+func absPath(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
@@ -0,0 +1,138 @@
+package datadir
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Migrate 把 src 中的数据文件移动到当前数据目录，返回移动的文件数
+//
+// 目标目录中已有同名文件时不做任何移动并返回错误，避免覆盖数据。
+// 锁文件和临时文件不迁移。迁移期间程序的其他实例不应运行。
+func Migrate(src string) (int, error) {
+	src = absPath(src)
+	dst := Get()
+	if samePath(src, dst) {
+		return 0, &SameDirError{Dir: dst}
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return 0, err
+	}
+
+	var names, conflicts []string
+	for _, e := range entries {
+		if e.IsDir() || skipOnMigrate(e.Name()) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dst, e.Name())); err == nil {
+			conflicts = append(conflicts, e.Name())
+		}
+		names = append(names, e.Name())
+	}
+	if len(conflicts) > 0 {
+		return 0, &ConflictError{Dir: dst, Names: conflicts}
+	}
+
+	moved := 0
+	for _, name := range names {
+		if err := moveFile(filepath.Join(src, name), filepath.Join(dst, name)); err != nil {
+			return moved, &MoveError{Name: name, Err: err}
+		}
+		moved++
+	}
+
+	// 清理残留的锁文件和空目录
+	for _, e := range entries {
+		if !e.IsDir() && skipOnMigrate(e.Name()) {
+			_ = os.Remove(filepath.Join(src, e.Name()))
+		}
+	}
+	_ = os.Remove(src)
+	return moved, nil
+}
+
+// 迁移错误（界面显示的文字由调用方本地化）
+type (
+	// SameDirError 源目录就是当前数据目录
+	SameDirError struct {
+		Dir string
+	}
+	// ConflictError 目标目录已有同名文件
+	ConflictError struct {
+		Dir   string
+		Names []string
+	}
+	// MoveError 移动某个文件失败
+	MoveError struct {
+		Name string
+		Err  error
+	}
+)
+
+func (e *SameDirError) Error() string {
+	return "source is the current data directory: " + e.Dir
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("files already exist in %s: %s", e.Dir, strings.Join(e.Names, ", "))
+}
+
+func (e *MoveError) Error() string {
+	return fmt.Sprintf("move %s: %v", e.Name, e.Err)
+}
+
+func (e *MoveError) Unwrap() error {
+	return e.Err
+}
+
+func skipOnMigrate(name string) bool {
+	return strings.HasSuffix(name, ".lock") || strings.HasSuffix(name, ".tmp") || name == PortableMarker
+}
+
+// moveFile 优先改名；跨分区（如移动到 U 盘）时复制后删除源文件
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
+
+func samePath(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ai, err1 := os.Stat(a)
+	bi, err2 := os.Stat(b)
+	return err1 == nil && err2 == nil && os.SameFile(ai, bi)
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+// 记录日志采用 JSON Lines 格式：每行一条记录，只追加不重写。
+// 崩溃时最多损坏最后一行，读取时会跳过损坏的行并把它们保存到 name.corrupt。
+
+// AppendRecords 追加写入记录
+func AppendRecords[T any](name string, records ...T) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(Path(name), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// 上次写入中断时文件末尾没有换行，先补一个，避免新记录与残缺行粘在一起
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := f.Write([]byte{'\n'}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// ReadRecords 读取全部记录（文件不存在时返回空）
+func ReadRecords[T any](name string) ([]T, error) {
+	unlock, err := Lock(name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return readRecords[T](name)
+}
+
+// RewriteRecords 原子地重写整个日志，用于清理过期记录或合并压缩
+func RewriteRecords[T any](name string, records []T) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return rewriteRecords(name, records)
+}
+
+// UpdateRecords 在文件锁内读取全部记录，经 fn 处理后原子重写
+func UpdateRecords[T any](name string, fn func([]T) ([]T, error)) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	records, err := readRecords[T](name)
+	if err != nil {
+		return err
+	}
+	records, err = fn(records)
+	if err != nil {
+		return err
+	}
+	return rewriteRecords(name, records)
+}
+
+func readRecords[T any](name string) ([]T, error) {
+	data, err := os.ReadFile(Path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []T
+	var corrupt [][]byte
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var r T
+		if err := json.Unmarshal(line, &r); err != nil {
+			corrupt = append(corrupt, line)
+			continue
+		}
+		records = append(records, r)
+	}
+
+	// 损坏行成功保留后才重写日志，下次读取不再重复备份
+	if len(corrupt) > 0 && saveCorruptLines(name, corrupt) == nil {
+		_ = rewriteRecords(name, records)
+	}
+	return records, nil
+}
+
+// saveCorruptLines 把损坏的行追加到 name.corrupt
+func saveCorruptLines(name string, lines [][]byte) error {
+	f, err := os.OpenFile(Path(name+".corrupt"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := f.Write(append(bytes.Clone(line), '\n')); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+func rewriteRecords[T any](name string, records []T) error {
+	var buf bytes.Buffer
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return writeFileAtomic(Path(name), buf.Bytes(), false)
+}
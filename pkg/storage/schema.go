@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"os"
+	"time"
+)
+
+// 数据目录的格式版本记录在 schema.json 中。
+// 数据格式变化时在程序启动时按版本号依次执行迁移，每完成一步立即保存版本号，
+// 中途失败或崩溃时下次启动从未完成的那一步重新执行，因此每个迁移都必须可重复执行。
+
+const schemaFile = "schema.json"
+
+// Migration 一次数据格式迁移
+type Migration struct {
+	Version int          // 迁移完成后的版本号，从 1 开始递增
+	Name    string       // 简短说明，记录到 schema.json
+	Apply   func() error // 迁移逻辑，需可重复执行
+}
+
+type schemaState struct {
+	Version int                `json:"version"`
+	Applied []appliedMigration `json:"applied"`
+}
+
+type appliedMigration struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+	Time    string `json:"time"`
+}
+
+// SchemaVersion 返回数据目录当前的格式版本（从未迁移过为 0）
+func SchemaVersion() (int, error) {
+	var state schemaState
+	if err := Load(schemaFile, &state); err != nil {
+		return 0, err
+	}
+	return state.Version, nil
+}
+
+// Migrate 按版本号依次执行高于当前版本的迁移
+func Migrate(migrations []Migration) error {
+	unlock, err := Lock(schemaFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := Path(schemaFile)
+	var state schemaState
+	if err := readJSON(path, &state); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= state.Version {
+			continue
+		}
+		if err := m.Apply(); err != nil {
+			return err
+		}
+		state.Version = m.Version
+		state.Applied = append(state.Applied, appliedMigration{
+			Version: m.Version,
+			Name:    m.Name,
+			Time:    time.Now().Format(time.RFC3339),
+		})
+		if err := writeJSON(path, &state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exists 数据目录下的文件是否存在
+func Exists(name string) bool {
+	_, err := os.Stat(Path(name))
+	return err == nil
+}
+
+// Backup 把文件复制为 name+suffix（已存在同名备份时不覆盖，保留最早的版本）
+func Backup(name, suffix string) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	dst := Path(name + suffix)
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(Path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return writeFileAtomic(dst, data, false)
+}
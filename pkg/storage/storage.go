@@ -0,0 +1,192 @@
+// Package storage 数据目录下的持久化存储：原子写入、跨进程文件锁、损坏恢复、只追加的记录日志
+//
+// JSON 文件写入流程：先写入 name.tmp 并落盘，再把旧文件改名为 name.bak，最后把 name.tmp 改名为 name。
+// 读取时若文件损坏，会把损坏文件改名为 name.corrupt-时间戳 保留下来，并从 name.bak 恢复。
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"win-cleaner/pkg/datadir"
+)
+
+// 进程内互斥锁（文件锁只保证跨进程互斥，同一进程内的协程仍需串行）
+var (
+	mutexes   = make(map[string]*sync.Mutex)
+	mutexesMu sync.Mutex
+)
+
+func fileMutex(name string) *sync.Mutex {
+	mutexesMu.Lock()
+	defer mutexesMu.Unlock()
+	m, ok := mutexes[name]
+	if !ok {
+		m = &sync.Mutex{}
+		mutexes[name] = m
+	}
+	return m
+}
+
+// Path 返回数据目录下文件的完整路径
+func Path(name string) string {
+	return datadir.FilePath(name)
+}
+
+// Lock 获取指定文件的锁（进程内 + 跨进程），返回解锁函数
+func Lock(name string) (func(), error) {
+	m := fileMutex(name)
+	m.Lock()
+
+	f, err := os.OpenFile(Path(name+".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		m.Unlock()
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		m.Unlock()
+		return nil, err
+	}
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+		m.Unlock()
+	}, nil
+}
+
+// Load 读取 JSON 文件到 v（文件不存在时 v 保持零值）
+func Load(name string, v any) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return readJSON(Path(name), v)
+}
+
+// Save 原子写入 JSON 文件
+func Save(name string, v any) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeJSON(Path(name), v)
+}
+
+// Update 在文件锁内完成 读取 → 修改 → 写回，fn 返回错误时不写回
+func Update(name string, v any, fn func() error) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := Path(name)
+	if err := readJSON(path, v); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return writeJSON(path, v)
+}
+
+// readJSON 读取并解析 JSON，损坏或缺失时尝试从 .bak 恢复
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// 可能在两次改名之间崩溃，主文件缺失但备份仍在
+			return restoreBackup(path, v)
+		}
+		return err
+	}
+	if err := decode(data, v); err != nil {
+		if err := backupCorrupt(path); err != nil {
+			return err
+		}
+		return restoreBackup(path, v)
+	}
+	return nil
+}
+
+// restoreBackup 从 .bak 恢复数据并写回主文件；没有可用备份时 v 保持零值
+func restoreBackup(path string, v any) error {
+	bak := path + ".bak"
+	data, err := os.ReadFile(bak)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := decode(data, v); err != nil {
+		return backupCorrupt(bak)
+	}
+	return writeFileAtomic(path, data, false)
+}
+
+// decode 解析 JSON，失败时把 v 重置为零值，避免残留部分数据
+func decode(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv.Elem().SetZero()
+		}
+		return err
+	}
+	return nil
+}
+
+// backupCorrupt 把损坏的文件改名保留，便于人工恢复
+func backupCorrupt(path string) error {
+	dst := path + ".corrupt-" + time.Now().Format("20060102-150405")
+	if err := os.Rename(path, dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, true)
+}
+
+// writeFileAtomic 先写临时文件并落盘，再改名替换；keepBackup 为 true 时保留上一版本为 .bak
+func writeFileAtomic(path string, data []byte, keepBackup bool) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if keepBackup {
+		if err := os.Rename(path, path+".bak"); err != nil && !errors.Is(err, os.ErrNotExist) {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,95 @@
+// Package timeutil 历史记录的时间戳格式
+//
+// 所有历史记录统一保存为带时区偏移的 RFC 3339 时间戳（如 2024-05-01T08:30:00+08:00），
+// 按天/月统计时使用记录产生时所在时区的日期，跨时区出行或夏令时切换都不会打乱汇总。
+package timeutil
+
+import "time"
+
+// Layout 历史记录时间戳格式
+const Layout = time.RFC3339
+
+// DisplayLayout 界面显示格式（本地时间）
+const DisplayLayout = "2006-01-02 15:04:05"
+
+// 旧版数据使用的不带时区的本地时间格式
+var legacyLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15",
+	"2006-01-02",
+}
+
+// Format 格式化为带时区偏移的时间戳（精确到秒）
+func Format(t time.Time) string {
+	return t.Format(Layout)
+}
+
+// Now 当前时间的时间戳
+func Now() string {
+	return Format(time.Now())
+}
+
+// Parse 解析时间戳，保留其中的时区偏移
+//
+// 兼容旧版不带时区的本地时间字符串，按本机时区解析（夏令时按当时的规则确定偏移）。
+func Parse(s string) (time.Time, error) {
+	if t, err := time.Parse(Layout, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range legacyLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, &ParseError{Value: s}
+}
+
+// ParseError 无法识别的时间字符串（界面显示的文字由调用方本地化）
+type ParseError struct {
+	Value string
+}
+
+func (e *ParseError) Error() string {
+	return "invalid time: " + e.Value
+}
+
+// IsRFC3339 判断字符串是否已是带时区的时间戳
+func IsRFC3339(s string) bool {
+	_, err := time.Parse(Layout, s)
+	return err == nil
+}
+
+// Day 记录所在时区的日期 YYYY-MM-DD
+func Day(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// Month 记录所在时区的月份 YYYY-MM
+func Month(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// Year 记录所在时区的年份 YYYY
+func Year(t time.Time) string {
+	return t.Format("2006")
+}
+
+// Display 转换为本机当前时区的显示格式，无法解析时原样返回
+func Display(s string) string {
+	t, err := Parse(s)
+	if err != nil {
+		return s
+	}
+	return t.Local().Format(DisplayLayout)
+}
+
+// Before 比较两个时间戳的先后（按实际时刻，不受时区偏移影响）；无法解析时按字符串比较
+func Before(a, b string) bool {
+	ta, errA := Parse(a)
+	tb, errB := Parse(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return ta.Before(tb)
+}
@@ -0,0 +1,7 @@
+// Package userdirs 定位系统用户目录（下载、文档等），不硬编码路径
+package userdirs
+
+import "errors"
+
+// ErrNotFound 系统未配置对应的用户目录（界面显示的文字由调用方本地化）
+var ErrNotFound = errors.New("user directory not found")
@@ -0,0 +1,53 @@
+//go:build !windows
+
+package userdirs
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Downloads 读取 XDG user-dirs 配置（$XDG_CONFIG_HOME/user-dirs.dirs）中的 XDG_DOWNLOAD_DIR
+func Downloads() (string, error) {
+	return lookupXDG("XDG_DOWNLOAD_DIR")
+}
+
+// lookupXDG 解析 user-dirs.dirs，格式为 KEY="$HOME/Downloads"
+func lookupXDG(key string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", ErrNotFound
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	f, err := os.Open(filepath.Join(configHome, "user-dirs.dirs"))
+	if err != nil {
+		return "", ErrNotFound
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(name) != key {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		value = strings.Replace(value, "$HOME", home, 1)
+		// 按规范，值为 $HOME/ 表示该目录被禁用
+		if value == "" || filepath.Clean(value) == filepath.Clean(home) {
+			return "", ErrNotFound
+		}
+		return value, nil
+	}
+	return "", ErrNotFound
+}
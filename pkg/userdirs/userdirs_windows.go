@@ -0,0 +1,14 @@
+package userdirs
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// Downloads 通过 Known Folder (FOLDERID_Downloads) 获取下载目录，支持用户重定向后的位置
+func Downloads() (string, error) {
+	path, err := windows.KnownFolderPath(windows.FOLDERID_Downloads, windows.KF_FLAG_DEFAULT)
+	if err != nil || path == "" {
+		return "", ErrNotFound
+	}
+	return path, nil
+}
@@ -0,0 +1,32 @@
+package winapi
+
+import "strings"
+
+// GetInstalledApps 从注册表卸载项读取已安装程序的显示名称（含 32/64 位与当前用户）
+func GetInstalledApps() ([]string, error) {
+	script := `
+$paths = @(
+  'HKLM:\Software\Microsoft\Windows\CurrentVersion\Uninstall\*',
+  'HKLM:\Software\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall\*',
+  'HKCU:\Software\Microsoft\Windows\CurrentVersion\Uninstall\*'
+)
+Get-ItemProperty -Path $paths -ErrorAction SilentlyContinue |
+  Where-Object { $_.DisplayName } |
+  ForEach-Object { $_.DisplayName }`
+	output, err := HiddenCmd("powershell", "-NoProfile", "-Command", script).Output()
+	if err != nil {
+		return nil, &CommandError{Op: OpInstalledApps, Err: err}
+	}
+
+	seen := make(map[string]bool)
+	var apps []string
+	for _, line := range strings.Split(string(output), "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		apps = append(apps, name)
+	}
+	return apps, nil
+}
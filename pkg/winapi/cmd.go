@@ -2,9 +2,40 @@ package winapi
 
 import (
 	"os/exec"
+	"strings"
 	"syscall"
 )
 
+// CommandError 外部命令（PowerShell 等）执行失败，界面显示的文字由调用方按 Op 本地化
+type CommandError struct {
+	Op     string // 所做的操作，见 Op* 常量
+	Err    error
+	Stderr string
+}
+
+// CommandError.Op 的取值
+const (
+	OpRecycleBinInfo  = "recycle_bin_info"
+	OpEmptyRecycleBin = "empty_recycle_bin"
+	OpInstalledApps   = "installed_apps"
+)
+
+// Detail 原始错误及命令的错误输出
+func (e *CommandError) Detail() string {
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		return e.Err.Error() + ", stderr: " + stderr
+	}
+	return e.Err.Error()
+}
+
+func (e *CommandError) Error() string {
+	return e.Op + ": " + e.Detail()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
 // HiddenCmd 创建一个隐藏窗口的 exec.Cmd（不弹出 PowerShell 黑窗口）
 func HiddenCmd(name string, args ...string) *exec.Cmd {
 	cmd := exec.Command(name, args...)
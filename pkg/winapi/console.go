@@ -0,0 +1,39 @@
+package winapi
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+var procAttachConsole = modKernel32.NewProc("AttachConsole")
+
+// ATTACH_PARENT_PROCESS，即 (DWORD)-1
+const attachParentProcess = ^uint32(0)
+
+// AttachParentConsole 附加到启动本程序的命令行窗口，并把 os.Stdout/os.Stderr 指向它
+//
+// 以 -H windowsgui 编译的程序没有控制台，直接输出的内容不可见；从资源管理器启动时没有父控制台，返回 false。
+func AttachParentConsole() bool {
+	if ret, _, _ := procAttachConsole.Call(uintptr(attachParentProcess)); ret == 0 {
+		return false
+	}
+	out, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0)
+	if err != nil {
+		return false
+	}
+	os.Stdout = out
+	os.Stderr = out
+	return true
+}
+
+// MessageBox 弹出消息框（无控制台时显示命令行结果）
+func MessageBox(title, text string, isError bool) {
+	var flags uint32 = windows.MB_OK | windows.MB_ICONINFORMATION
+	if isError {
+		flags = windows.MB_OK | windows.MB_ICONERROR
+	}
+	t, _ := windows.UTF16PtrFromString(title)
+	m, _ := windows.UTF16PtrFromString(text)
+	_, _ = windows.MessageBox(0, m, t, flags)
+}
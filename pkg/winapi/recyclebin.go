@@ -30,7 +30,7 @@ func GetRecycleBinInfo() (RecycleBinInfo, error) {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return RecycleBinInfo{}, fmt.Errorf("获取回收站信息失败: %w, stderr: %s", err, stderr.String())
+		return RecycleBinInfo{}, &CommandError{Op: OpRecycleBinInfo, Err: err, Stderr: stderr.String()}
 	}
 
 	output := strings.TrimSpace(stdout.String())
@@ -40,17 +40,17 @@ func GetRecycleBinInfo() (RecycleBinInfo, error) {
 
 	parts := strings.Split(output, "|")
 	if len(parts) != 2 {
-		return RecycleBinInfo{}, fmt.Errorf("回收站信息格式错误: %s", output)
+		return RecycleBinInfo{}, &CommandError{Op: OpRecycleBinInfo, Err: fmt.Errorf("unexpected output %q", output)}
 	}
 
 	count, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
-		return RecycleBinInfo{}, fmt.Errorf("解析回收站数量失败: %w", err)
+		return RecycleBinInfo{}, &CommandError{Op: OpRecycleBinInfo, Err: fmt.Errorf("parse count: %w", err)}
 	}
 
 	size, err := strconv.ParseInt(parts[1], 10, 64)
 	if err != nil {
-		return RecycleBinInfo{}, fmt.Errorf("解析回收站大小失败: %w", err)
+		return RecycleBinInfo{}, &CommandError{Op: OpRecycleBinInfo, Err: fmt.Errorf("parse size: %w", err)}
 	}
 
 	return RecycleBinInfo{
@@ -66,7 +66,7 @@ func EmptyRecycleBin() error {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("清空回收站失败: %w, stderr: %s", err, stderr.String())
+		return &CommandError{Op: OpEmptyRecycleBin, Err: err, Stderr: stderr.String()}
 	}
 	return nil
 }
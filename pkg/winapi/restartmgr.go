@@ -0,0 +1,112 @@
+package winapi
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modRstrtmgr             = windows.NewLazySystemDLL("rstrtmgr.dll")
+	procRmStartSession      = modRstrtmgr.NewProc("RmStartSession")
+	procRmRegisterResources = modRstrtmgr.NewProc("RmRegisterResources")
+	procRmGetList           = modRstrtmgr.NewProc("RmGetList")
+	procRmEndSession        = modRstrtmgr.NewProc("RmEndSession")
+)
+
+const (
+	rmSessionKeyLen = 32 + 1  // CCH_RM_SESSION_KEY + 1
+	rmMaxAppName    = 255 + 1 // CCH_RM_MAX_APP_NAME + 1
+	rmMaxSvcName    = 63 + 1  // CCH_RM_MAX_SVC_NAME + 1
+	errorMoreData   = 234     // ERROR_MORE_DATA
+)
+
+// ErrProcessListChanging 占用文件的进程列表在多次查询中一直变化，无法得到稳定结果
+var ErrProcessListChanging = errors.New("RmGetList failed: process list kept changing")
+
+// rmProcessInfo 对应 RM_PROCESS_INFO
+type rmProcessInfo struct {
+	ProcessID        uint32
+	ProcessStartTime windows.Filetime
+	AppName          [rmMaxAppName]uint16
+	ServiceShortName [rmMaxSvcName]uint16
+	ApplicationType  uint32
+	AppStatus        uint32
+	TSSessionID      uint32
+	Restartable      int32
+}
+
+// FileLockProcess 占用文件的进程
+type FileLockProcess struct {
+	PID         uint32
+	AppName     string // Restart Manager 提供的友好名称
+	ServiceName string // 若为服务则为服务短名
+}
+
+// GetFileLockers 通过 Restart Manager 查询占用指定文件的进程
+func GetFileLockers(path string) ([]FileLockProcess, error) {
+	var session uint32
+	key := make([]uint16, rmSessionKeyLen)
+	ret, _, _ := procRmStartSession.Call(
+		uintptr(unsafe.Pointer(&session)),
+		0,
+		uintptr(unsafe.Pointer(&key[0])),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("RmStartSession failed: %d", ret)
+	}
+	defer procRmEndSession.Call(uintptr(session))
+
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	ret, _, _ = procRmRegisterResources.Call(
+		uintptr(session),
+		1,
+		uintptr(unsafe.Pointer(&name)),
+		0, 0, 0, 0,
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("RmRegisterResources failed: %d", ret)
+	}
+
+	// 先查询需要的数量，进程列表可能在两次调用之间变化，因此循环重试
+	var infos []rmProcessInfo
+	for attempt := 0; attempt < 3; attempt++ {
+		var needed, count uint32
+		var reasons uint32
+		count = uint32(len(infos))
+		var ptr uintptr
+		if count > 0 {
+			ptr = uintptr(unsafe.Pointer(&infos[0]))
+		}
+		ret, _, _ = procRmGetList.Call(
+			uintptr(session),
+			uintptr(unsafe.Pointer(&needed)),
+			uintptr(unsafe.Pointer(&count)),
+			ptr,
+			uintptr(unsafe.Pointer(&reasons)),
+		)
+		if ret == errorMoreData {
+			infos = make([]rmProcessInfo, needed)
+			continue
+		}
+		if ret != 0 {
+			return nil, fmt.Errorf("RmGetList failed: %d", ret)
+		}
+
+		result := make([]FileLockProcess, 0, count)
+		for _, info := range infos[:count] {
+			result = append(result, FileLockProcess{
+				PID:         info.ProcessID,
+				AppName:     windows.UTF16ToString(info.AppName[:]),
+				ServiceName: windows.UTF16ToString(info.ServiceShortName[:]),
+			})
+		}
+		return result, nil
+	}
+	return nil, ErrProcessListChanging
+}